@@ -0,0 +1,274 @@
+package gcache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type Cache interface {
+	// Set a new key-value pair
+	Set(key, value interface{}) error
+	// Set a new key-value pair with an expiration time
+	SetWithExpire(key, value interface{}, expiration time.Duration) error
+	// Get a value from cache pool using key if it exists.
+	// If it does not exists key and has LoaderFunc,
+	// generate a value using `LoaderFunc` method returns value.
+	Get(key interface{}) (interface{}, error)
+	// Get a value from cache pool using key if it exists.
+	// If it dose not exists key, returns KeyNotFoundError.
+	GetIFPresent(key interface{}) (interface{}, error)
+	// Returns all key-value pairs in the cache.
+	GetALL() map[interface{}]interface{}
+	get(key interface{}, onLoad bool) (interface{}, error)
+	// Removes the provided key from the cache.
+	Remove(key interface{}) error
+	// Returns a slice of the keys in the cache.
+	Keys() []interface{}
+	// Returns the number of items in the cache.
+	Len() int
+	// Completely clear the cache
+	Purge()
+	// Close stops the background reaper or timing wheel goroutine started
+	// by WithReaper/WithTimingWheel, if any. Safe to call even if neither
+	// was configured, and safe to call more than once.
+	Close() error
+
+	statsAccessor
+}
+
+type (
+	LoaderFunc       func(interface{}) (interface{}, error)
+	LoaderExpireFunc func(interface{}) (interface{}, *time.Duration, error)
+	EvictedFunc      func(interface{}, interface{})
+	PurgeVisitorFunc func(interface{}, interface{})
+	AddedFunc        func(interface{}, interface{})
+	DeserializeFunc  func(interface{}, interface{}) (interface{}, error)
+	SerializeFunc    func(interface{}, interface{}) (interface{}, error)
+)
+
+// KeyNotFoundError is returned by Get/GetIFPresent when the key is absent
+// from the cache and cannot be (or should not be) loaded.
+var KeyNotFoundError = errors.New("gcache: key not found")
+
+type CacheBuilder struct {
+	clock            Clock
+	tp               string
+	size             int
+	capacity         int
+	loaderExpireFunc LoaderExpireFunc
+	evictedFunc      EvictedFunc
+	purgeVisitorFunc PurgeVisitorFunc
+	addedFunc        AddedFunc
+	expiration       *time.Duration
+	deserializeFunc  DeserializeFunc
+	serializeFunc    SerializeFunc
+	recentRatio      float64
+	ghostRatio       float64
+	reaperInterval   time.Duration
+	wheelTick        time.Duration
+	wheelSlots       int
+	expiryJitter     float64
+	encodeFunc       EncodeFunc
+	decodeFunc       DecodeFunc
+}
+
+func New(size int) *CacheBuilder {
+	return &CacheBuilder{
+		clock:       NewRealClock(),
+		tp:          TYPE_SIMPLE,
+		size:        size,
+		capacity:    size,
+		recentRatio: TwoQueueDefaultRecentRatio,
+		ghostRatio:  TwoQueueDefaultGhostRatio,
+	}
+}
+
+func (cb *CacheBuilder) Clock(clock Clock) *CacheBuilder {
+	cb.clock = clock
+	return cb
+}
+
+// Set a loader function.
+// loaderFunc: create a new value with this function if cached value is expired.
+func (cb *CacheBuilder) LoaderFunc(loaderFunc LoaderFunc) *CacheBuilder {
+	cb.loaderExpireFunc = func(k interface{}) (interface{}, *time.Duration, error) {
+		v, err := loaderFunc(k)
+		return v, nil, err
+	}
+	return cb
+}
+
+// Set a loader function with expiration.
+// loaderExpireFunc: create a new value with this function if cached value is expired.
+// If nil returned instead of time.Duration from loaderExpireFunc than value never expire.
+func (cb *CacheBuilder) LoaderExpireFunc(loaderExpireFunc LoaderExpireFunc) *CacheBuilder {
+	cb.loaderExpireFunc = loaderExpireFunc
+	return cb
+}
+
+func (cb *CacheBuilder) EvictType(tp string) *CacheBuilder {
+	cb.tp = tp
+	return cb
+}
+
+func (cb *CacheBuilder) Simple() *CacheBuilder {
+	return cb.EvictType(TYPE_SIMPLE)
+}
+
+func (cb *CacheBuilder) LRU() *CacheBuilder {
+	return cb.EvictType(TYPE_LRU)
+}
+
+func (cb *CacheBuilder) TwoQueue() *CacheBuilder {
+	return cb.EvictType(TYPE_TWO_QUEUE)
+}
+
+func (cb *CacheBuilder) Sieve() *CacheBuilder {
+	return cb.EvictType(TYPE_SIEVE)
+}
+
+// RecentRatio sets the fraction of capacity given to the 2Q recent (Am)
+// list. Only meaningful when the TwoQueue policy is selected.
+func (cb *CacheBuilder) RecentRatio(recentRatio float64) *CacheBuilder {
+	cb.recentRatio = recentRatio
+	return cb
+}
+
+// GhostRatio sets the fraction of capacity given to the 2Q ghost (A1out)
+// list of recently-evicted keys. Only meaningful when the TwoQueue policy
+// is selected.
+func (cb *CacheBuilder) GhostRatio(ghostRatio float64) *CacheBuilder {
+	cb.ghostRatio = ghostRatio
+	return cb
+}
+
+func (cb *CacheBuilder) EvictedFunc(evictedFunc EvictedFunc) *CacheBuilder {
+	cb.evictedFunc = evictedFunc
+	return cb
+}
+
+func (cb *CacheBuilder) PurgeVisitorFunc(purgeVisitorFunc PurgeVisitorFunc) *CacheBuilder {
+	cb.purgeVisitorFunc = purgeVisitorFunc
+	return cb
+}
+
+func (cb *CacheBuilder) AddedFunc(addedFunc AddedFunc) *CacheBuilder {
+	cb.addedFunc = addedFunc
+	return cb
+}
+
+func (cb *CacheBuilder) DeserializeFunc(deserializeFunc DeserializeFunc) *CacheBuilder {
+	cb.deserializeFunc = deserializeFunc
+	return cb
+}
+
+func (cb *CacheBuilder) SerializeFunc(serializeFunc SerializeFunc) *CacheBuilder {
+	cb.serializeFunc = serializeFunc
+	return cb
+}
+
+func (cb *CacheBuilder) Expiration(expiration time.Duration) *CacheBuilder {
+	cb.expiration = &expiration
+	return cb
+}
+
+func (cb *CacheBuilder) Build() Cache {
+	return cb.build()
+}
+
+func (cb *CacheBuilder) build() Cache {
+	switch cb.tp {
+	case TYPE_LRU:
+		return newLRUCache(cb)
+	case TYPE_TWO_QUEUE:
+		return newTwoQueueCache(cb)
+	case TYPE_SIEVE:
+		return newSieveCache(cb)
+	default:
+		return newSimpleCache(cb)
+	}
+}
+
+type baseCache struct {
+	clock            Clock
+	size             int
+	capacity         int
+	loaderExpireFunc LoaderExpireFunc
+	evictedFunc      EvictedFunc
+	purgeVisitorFunc PurgeVisitorFunc
+	addedFunc        AddedFunc
+	expiration       *time.Duration
+	deserializeFunc  DeserializeFunc
+	serializeFunc    SerializeFunc
+	mu               sync.RWMutex
+	loadGroup        Group
+	subs             subscriptions
+	*stats
+
+	reaperInterval time.Duration
+	wheelTick      time.Duration
+	wheelSlots     int
+	expiryJitter   float64
+	wheel          *timingWheel
+	self           policyHooks
+	encodeFunc     EncodeFunc
+	decodeFunc     DecodeFunc
+
+	// currentGeneration and length make Purge O(1): Purge just bumps the
+	// generation and zeroes length instead of reallocating the backing
+	// map/list. Entries stamped with a stale generation are treated as
+	// absent and lazily removed by get/remove/evict the next time they're
+	// touched, all under the usual c.mu.
+	currentGeneration int64
+	length            int
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func buildCache(c *baseCache, cb *CacheBuilder) {
+	c.clock = cb.clock
+	c.size = cb.size
+	c.capacity = cb.capacity
+	c.loaderExpireFunc = cb.loaderExpireFunc
+	c.expiration = cb.expiration
+	c.addedFunc = cb.addedFunc
+	c.deserializeFunc = cb.deserializeFunc
+	c.serializeFunc = cb.serializeFunc
+	c.evictedFunc = cb.evictedFunc
+	c.purgeVisitorFunc = cb.purgeVisitorFunc
+	c.reaperInterval = cb.reaperInterval
+	c.wheelTick = cb.wheelTick
+	c.wheelSlots = cb.wheelSlots
+	c.expiryJitter = cb.expiryJitter
+	c.encodeFunc = cb.encodeFunc
+	if c.encodeFunc == nil {
+		c.encodeFunc = gobEncode
+	}
+	c.decodeFunc = cb.decodeFunc
+	if c.decodeFunc == nil {
+		c.decodeFunc = gobDecode
+	}
+	c.stats = &stats{}
+}
+
+// load a value using the configured LoaderExpireFunc, collapsing concurrent
+// callers for the same key into a single call via loadGroup.
+func (c *baseCache) load(key interface{}, cb func(interface{}, *time.Duration, error) (interface{}, error), isWait bool) (interface{}, bool, error) {
+	return c.loadGroup.Do(key, func() (interface{}, error) {
+		v, expiration, err := c.loaderExpireFunc(key)
+		if err != nil {
+			return cb(nil, nil, err)
+		}
+		return cb(v, expiration, nil)
+	}, isWait)
+}
+
+const (
+	TYPE_SIMPLE    = "simple"
+	TYPE_LRU       = "lru"
+	TYPE_TWO_QUEUE = "two_queue"
+	TYPE_SIEVE     = "sieve"
+)
@@ -0,0 +1,20 @@
+package gcache
+
+import "time"
+
+// Clock is the source of time used by caches, so tests can substitute a
+// fake implementation instead of depending on wall-clock time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+// NewRealClock returns a Clock backed by the standard library's time.Now.
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,144 @@
+package gcache
+
+import "sync"
+
+// EvictionReason describes why an entry left the cache, so a subscriber can
+// tell a deliberate Remove from a capacity- or TTL-driven eviction.
+type EvictionReason int
+
+const (
+	// EvictionReasonDeleted means the entry was removed by an explicit
+	// call to Remove.
+	EvictionReasonDeleted EvictionReason = iota
+	// EvictionReasonCapacityReached means the entry was evicted to make
+	// room for a new one.
+	EvictionReasonCapacityReached
+	// EvictionReasonExpired means the entry's TTL had passed when it was
+	// next touched or when the WithReaper sweep reached it.
+	EvictionReasonExpired
+	// EvictionReasonReplaced means the entry was overwritten by a Set
+	// call for the same key.
+	EvictionReasonReplaced
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionReasonDeleted:
+		return "deleted"
+	case EvictionReasonCapacityReached:
+		return "capacity_reached"
+	case EvictionReasonExpired:
+		return "expired"
+	case EvictionReasonReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// OnEvictionFunc is called whenever an entry leaves the cache, for whatever
+// reason. Unlike EvictedFunc, it is additive: subscribing does not replace
+// the CacheBuilder's EvictedFunc.
+type OnEvictionFunc func(reason EvictionReason, key, value interface{})
+
+// OnInsertionFunc is called whenever an entry is added to, or overwritten
+// in, the cache.
+type OnInsertionFunc func(key, value interface{})
+
+// subscriptions holds the OnEviction/OnInsertion callbacks for a cache.
+// It has its own mutex, separate from baseCache.mu, because OnEviction,
+// OnInsertion and the unsubscribe handles they return must be safe to call
+// while a Get/Set on the same cache holds
+// baseCache.mu and is in the middle of firing a notification.
+type subscriptions struct {
+	mu        sync.Mutex
+	nextID    uint64
+	eviction  map[uint64]OnEvictionFunc
+	insertion map[uint64]OnInsertionFunc
+}
+
+// OnEviction subscribes fn to eviction events and returns a handle that
+// unsubscribes it.
+func (c *baseCache) OnEviction(fn OnEvictionFunc) func() {
+	c.subs.mu.Lock()
+	defer c.subs.mu.Unlock()
+
+	if c.subs.eviction == nil {
+		c.subs.eviction = make(map[uint64]OnEvictionFunc)
+	}
+	id := c.subs.nextID
+	c.subs.nextID++
+	c.subs.eviction[id] = fn
+
+	return func() {
+		c.subs.mu.Lock()
+		defer c.subs.mu.Unlock()
+		delete(c.subs.eviction, id)
+	}
+}
+
+// OnInsertion subscribes fn to insertion events and returns a handle that
+// unsubscribes it.
+func (c *baseCache) OnInsertion(fn OnInsertionFunc) func() {
+	c.subs.mu.Lock()
+	defer c.subs.mu.Unlock()
+
+	if c.subs.insertion == nil {
+		c.subs.insertion = make(map[uint64]OnInsertionFunc)
+	}
+	id := c.subs.nextID
+	c.subs.nextID++
+	c.subs.insertion[id] = fn
+
+	return func() {
+		c.subs.mu.Lock()
+		defer c.subs.mu.Unlock()
+		delete(c.subs.insertion, id)
+	}
+}
+
+// fireEviction invokes the legacy EvictedFunc (if any) followed by every
+// OnEviction subscriber.
+func (c *baseCache) fireEviction(reason EvictionReason, key, value interface{}) {
+	if c.evictedFunc != nil {
+		c.evictedFunc(key, value)
+	}
+
+	c.subs.mu.Lock()
+	if len(c.subs.eviction) == 0 {
+		c.subs.mu.Unlock()
+		return
+	}
+	fns := make([]OnEvictionFunc, 0, len(c.subs.eviction))
+	for _, fn := range c.subs.eviction {
+		fns = append(fns, fn)
+	}
+	c.subs.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(reason, key, value)
+	}
+}
+
+// fireInsertion invokes the legacy AddedFunc (if any) followed by every
+// OnInsertion subscriber.
+func (c *baseCache) fireInsertion(key, value interface{}) {
+	if c.addedFunc != nil {
+		c.addedFunc(key, value)
+	}
+
+	c.subs.mu.Lock()
+	if len(c.subs.insertion) == 0 {
+		c.subs.mu.Unlock()
+		return
+	}
+	fns := make([]OnInsertionFunc, 0, len(c.subs.insertion))
+	for _, fn := range c.subs.insertion {
+		fns = append(fns, fn)
+	}
+	c.subs.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(key, value)
+	}
+}
@@ -0,0 +1,123 @@
+package gcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnInsertionFires(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	var gotKey, gotValue interface{}
+	unsub := cache.OnInsertion(func(key, value interface{}) {
+		gotKey, gotValue = key, value
+	})
+	defer unsub()
+
+	cache.Set("key", "value")
+
+	if gotKey != "key" || gotValue != "value" {
+		t.Fatalf("OnInsertion fired with (%v, %v), want (key, value)", gotKey, gotValue)
+	}
+}
+
+func TestOnEvictionFiresWithReason(t *testing.T) {
+	cache := New(1).LRU().Build().(*LRUCache)
+
+	var gotReason EvictionReason
+	var gotKey interface{}
+	cache.OnEviction(func(reason EvictionReason, key, value interface{}) {
+		gotReason, gotKey = reason, key
+	})
+
+	cache.Set("a", 1)
+	cache.Set("b", 2) // evicts "a" for capacity
+
+	if gotReason != EvictionReasonCapacityReached {
+		t.Fatalf("reason = %v, want %v", gotReason, EvictionReasonCapacityReached)
+	}
+	if gotKey != "a" {
+		t.Fatalf("evicted key = %v, want %q", gotKey, "a")
+	}
+}
+
+func TestOnEvictionFiresOnRemove(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	var gotReason EvictionReason
+	cache.OnEviction(func(reason EvictionReason, key, value interface{}) {
+		gotReason = reason
+	})
+
+	cache.Set("key", "value")
+	cache.Remove("key")
+
+	if gotReason != EvictionReasonDeleted {
+		t.Fatalf("reason = %v, want %v", gotReason, EvictionReasonDeleted)
+	}
+}
+
+func TestOnEvictionFiresOnExpire(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	var gotReason EvictionReason
+	cache.OnEviction(func(reason EvictionReason, key, value interface{}) {
+		gotReason = reason
+	})
+
+	cache.SetWithExpire("key", "value", 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	cache.GetIFPresent("key")
+
+	if gotReason != EvictionReasonExpired {
+		t.Fatalf("reason = %v, want %v", gotReason, EvictionReasonExpired)
+	}
+}
+
+func TestOnEvictionFiresOnReplace(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	var gotReason EvictionReason
+	cache.OnEviction(func(reason EvictionReason, key, value interface{}) {
+		gotReason = reason
+	})
+
+	cache.Set("key", "value")
+	cache.Set("key", "value2")
+
+	if gotReason != EvictionReasonReplaced {
+		t.Fatalf("reason = %v, want %v", gotReason, EvictionReasonReplaced)
+	}
+}
+
+func TestUnsubscribeStopsNotifications(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	calls := 0
+	unsub := cache.OnInsertion(func(key, value interface{}) {
+		calls++
+	})
+
+	cache.Set("a", 1)
+	unsub()
+	cache.Set("b", 2)
+
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestEvictionReasonString(t *testing.T) {
+	cases := map[EvictionReason]string{
+		EvictionReasonDeleted:         "deleted",
+		EvictionReasonCapacityReached: "capacity_reached",
+		EvictionReasonExpired:         "expired",
+		EvictionReasonReplaced:        "replaced",
+		EvictionReason(99):            "unknown",
+	}
+	for reason, want := range cases {
+		if got := reason.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", reason, got, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package gcache
+
+import "sync"
+
+// call is an in-flight or completed load call for a single key.
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// Group collapses concurrent loads for the same key into a single call,
+// the way golang.org/x/sync/singleflight does, so a cache miss storm for a
+// hot key only triggers one call to the LoaderFunc.
+type Group struct {
+	cache Cache
+	mu    sync.Mutex
+	calls map[interface{}]*call
+}
+
+// Do executes fn for key, making sure only one execution is in-flight for a
+// given key at a time. If isWait is false, Do does not block: it starts fn
+// in the background (unless a call for key is already in flight) and
+// returns KeyNotFoundError at once.
+func (g *Group) Do(key interface{}, fn func() (interface{}, error), isWait bool) (interface{}, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[interface{}]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		if !isWait {
+			return nil, true, KeyNotFoundError
+		}
+		c.wg.Wait()
+		return c.value, true, c.err
+	}
+	c := new(call)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	if !isWait {
+		go func() {
+			c.value, c.err = fn()
+			c.wg.Done()
+
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+		}()
+		return nil, false, KeyNotFoundError
+	}
+
+	c.value, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return c.value, false, c.err
+}
@@ -16,6 +16,7 @@ type lruItem struct {
 	key        interface{}
 	value      interface{}
 	expiration *time.Time
+	generation int64
 }
 
 func newLRUCache(cb *CacheBuilder) *LRUCache {
@@ -24,6 +25,7 @@ func newLRUCache(cb *CacheBuilder) *LRUCache {
 
 	c.init()
 	c.loadGroup.cache = c
+	c.startBackgroundExpiry(c)
 	return c
 }
 
@@ -43,29 +45,41 @@ func (c *LRUCache) set(key, value interface{}) (interface{}, error) {
 
 	var item *lruItem
 	if it, ok := c.store[key]; ok {
-		c.evictList.MoveToFront(it)
-		item = it.Value.(*lruItem)
-		item.value = value
-	} else {
-		if c.evictList.Len() >= c.capacity {
+		existing := it.Value.(*lruItem)
+		if existing.generation == c.currentGeneration {
+			c.evictList.MoveToFront(it)
+			item = existing
+			oldValue := item.value
+			item.value = value
+			c.fireEviction(EvictionReasonReplaced, key, oldValue)
+		} else {
+			// Stale node left behind by a Purge; prune it and fall
+			// through to the insert path below.
+			c.evictList.Remove(it)
+			delete(c.store, key)
+		}
+	}
+	if item == nil {
+		if c.length >= c.capacity {
 			c.evict(1)
 		}
 		item = &lruItem{
-			clock: c.clock,
-			key:   key,
-			value: value,
+			clock:      c.clock,
+			key:        key,
+			value:      value,
+			generation: c.currentGeneration,
 		}
 		c.store[key] = c.evictList.PushFront(item)
+		c.length++
 	}
 
 	if c.expiration != nil {
-		t := c.clock.Now().Add(*c.expiration)
+		t := c.expiryTime(*c.expiration)
 		item.expiration = &t
+		c.scheduleExpiry(key, t)
 	}
 
-	if c.addedFunc != nil {
-		c.addedFunc(key, value)
-	}
+	c.fireInsertion(key, value)
 
 	return item, nil
 }
@@ -87,13 +101,20 @@ func (c *LRUCache) SetWithExpire(key, value interface{}, expiration time.Duratio
 		return err
 	}
 
-	t := c.clock.Now().Add(expiration)
+	t := c.expiryTime(expiration)
 	item.(*lruItem).expiration = &t
+	c.scheduleExpiry(key, t)
 	return nil
 }
 
 func (c *LRUCache) get(key interface{}, onLoad bool) (interface{}, error) {
 	entry, exists := c.store[key]
+	if exists && entry.Value.(*lruItem).generation != c.currentGeneration {
+		// Stale node left behind by a Purge; prune it lazily.
+		c.evictList.Remove(entry)
+		delete(c.store, key)
+		exists = false
+	}
 
 	if !exists {
 		if !onLoad {
@@ -104,7 +125,7 @@ func (c *LRUCache) get(key interface{}, onLoad bool) (interface{}, error) {
 
 	item := entry.Value.(*lruItem)
 	if item.isExpired(nil) {
-		c.removeElement(entry)
+		c.removeElementWithReason(entry, EvictionReasonExpired)
 		if !onLoad {
 			c.stats.IncrMissCount()
 		}
@@ -184,9 +205,28 @@ func (c *LRUCache) GetALL() map[interface{}]interface{} {
 	return m
 }
 
+// expiresAt reports key's current expiration time, for the timing wheel
+// to verify a scheduled key is actually due before removing it.
+func (c *LRUCache) expiresAt(key interface{}) (time.Time, bool) {
+	ent, ok := c.store[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	item := ent.Value.(*lruItem)
+	if item.generation != c.currentGeneration || item.expiration == nil {
+		return time.Time{}, false
+	}
+	return *item.expiration, true
+}
+
 func (c *LRUCache) remove(key interface{}) error {
 	if ent, ok := c.store[key]; ok {
-		c.removeElement(ent)
+		if ent.Value.(*lruItem).generation != c.currentGeneration {
+			c.evictList.Remove(ent)
+			delete(c.store, key)
+			return KeyNotFoundError
+		}
+		c.removeElementWithReason(ent, EvictionReasonDeleted)
 		return nil
 	}
 	return KeyNotFoundError
@@ -199,6 +239,10 @@ func (c *LRUCache) Remove(key interface{}) error {
 	return c.remove(key)
 }
 
+// Purge just bumps the current generation and zeroes the logical length,
+// so it is O(1) and safe to call while other goroutines are mid-Get:
+// nodes from the prior generation are lazily pruned the next time they're
+// touched.
 func (c *LRUCache) Purge() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -206,19 +250,21 @@ func (c *LRUCache) Purge() {
 	if c.purgeVisitorFunc != nil {
 		for key, item := range c.store {
 			it := item.Value.(*lruItem)
-			v := it.value
-			c.purgeVisitorFunc(key, v)
+			if it.generation == c.currentGeneration {
+				c.purgeVisitorFunc(key, it.value)
+			}
 		}
 	}
 
-	c.init()
+	c.currentGeneration++
+	c.length = 0
 }
 func (c *LRUCache) keys() []interface{} {
-	keys := make([]interface{}, len(c.store))
-	var i = 0
-	for k := range c.store {
-		keys[i] = k
-		i++
+	keys := make([]interface{}, 0, c.length)
+	for k, item := range c.store {
+		if item.Value.(*lruItem).generation == c.currentGeneration {
+			keys = append(keys, k)
+		}
 	}
 	return keys
 }
@@ -239,28 +285,42 @@ func (c *LRUCache) Keys() []interface{} {
 }
 
 func (c *LRUCache) Len() int {
-	return len(c.store)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.length
 }
 
 func (c *LRUCache) evict(count int) {
-	for i := 0; i < count; i++ {
+	for i := 0; i < count; {
 		ent := c.evictList.Back()
 		if ent == nil {
 			return
-		} else {
-			c.removeElement(ent)
 		}
+		if ent.Value.(*lruItem).generation != c.currentGeneration {
+			// Stale node from a prior generation; prune it without
+			// counting it as an eviction.
+			entry := ent.Value.(*lruItem)
+			c.evictList.Remove(ent)
+			delete(c.store, entry.key)
+			continue
+		}
+		c.removeElementWithReason(ent, EvictionReasonCapacityReached)
+		i++
 	}
 }
 
 func (c *LRUCache) removeElement(e *list.Element) {
+	c.removeElementWithReason(e, EvictionReasonDeleted)
+}
+
+func (c *LRUCache) removeElementWithReason(e *list.Element, reason EvictionReason) {
 	c.evictList.Remove(e)
 	entry := e.Value.(*lruItem)
 	delete(c.store, entry.key)
-	if c.evictedFunc != nil {
-		entry := e.Value.(*lruItem)
-		c.evictedFunc(entry.key, entry.value)
+	if entry.generation == c.currentGeneration {
+		c.length--
 	}
+	c.fireEviction(reason, entry.key, entry.value)
 }
 
 func (it *lruItem) isExpired(now *time.Time) bool {
@@ -274,6 +334,64 @@ func (it *lruItem) isExpired(now *time.Time) bool {
 	return it.expiration.Before(*now)
 }
 
+// snapshotEntries returns every live entry, front (MRU) to back (LRU),
+// for Save. It is called under c.mu's read lock.
+func (c *LRUCache) snapshotEntries() []snapshotEntry {
+	entries := make([]snapshotEntry, 0, c.length)
+	for e := c.evictList.Front(); e != nil; e = e.Next() {
+		it := e.Value.(*lruItem)
+		if it.generation != c.currentGeneration {
+			continue
+		}
+		entries = append(entries, snapshotEntry{Key: it.key, Value: it.value, Expiration: it.expiration})
+	}
+	return entries
+}
+
+// loadEntries restores entries from Load in MRU-to-LRU order, dropping
+// any already past their absolute expiration. It is called under c.mu's
+// write lock.
+func (c *LRUCache) loadEntries(entries []snapshotEntry) {
+	now := c.clock.Now()
+	for _, e := range entries {
+		if e.Expiration != nil && e.Expiration.Before(now) {
+			continue
+		}
+		if old, ok := c.store[e.Key]; ok {
+			c.evictList.Remove(old)
+			delete(c.store, e.Key)
+			if old.Value.(*lruItem).generation == c.currentGeneration {
+				c.length--
+			}
+		}
+		item := &lruItem{clock: c.clock, key: e.Key, value: e.Value, expiration: e.Expiration, generation: c.currentGeneration}
+		c.store[e.Key] = c.evictList.PushBack(item)
+		c.length++
+		if item.expiration != nil {
+			c.scheduleExpiry(e.Key, *item.expiration)
+		}
+	}
+}
+
+// reapExpired removes every currently-expired entry, pruning any stale
+// nodes left behind by a Purge along the way. It is called by the
+// background reaper under c.mu's write lock; it does not take the lock
+// itself.
+func (c *LRUCache) reapExpired() {
+	now := c.clock.Now()
+	for e := c.evictList.Back(); e != nil; {
+		prev := e.Prev()
+		it := e.Value.(*lruItem)
+		if it.generation != c.currentGeneration {
+			c.evictList.Remove(e)
+			delete(c.store, it.key)
+		} else if it.isExpired(&now) {
+			c.removeElementWithReason(e, EvictionReasonExpired)
+		}
+		e = prev
+	}
+}
+
 func (c *LRUCache) Debug() map[string][]int {
 	d := make(map[string][]int)
 	d["lru"] = []int{len(c.store), c.evictList.Len()}
@@ -0,0 +1,84 @@
+package gcache
+
+import "testing"
+
+// newPurgeTestCache builds a cache of each policy with the given
+// PurgeVisitorFunc, so the Purge behavior below can be verified
+// identically across all four generation-based policies.
+func newPurgeTestCache(t *testing.T, tp string, visitor PurgeVisitorFunc) Cache {
+	t.Helper()
+	return New(10).EvictType(tp).PurgeVisitorFunc(visitor).Build()
+}
+
+func TestPurgeAcrossPolicies(t *testing.T) {
+	for _, tp := range []string{TYPE_SIMPLE, TYPE_LRU, TYPE_TWO_QUEUE, TYPE_SIEVE} {
+		t.Run(tp, func(t *testing.T) {
+			visited := make(map[interface{}]interface{})
+			cache := newPurgeTestCache(t, tp, func(key, value interface{}) {
+				visited[key] = value
+			})
+
+			cache.Set("a", 1)
+			cache.Set("b", 2)
+
+			cache.Purge()
+
+			if len(visited) != 2 || visited["a"] != 1 || visited["b"] != 2 {
+				t.Fatalf("PurgeVisitorFunc saw %v, want {a:1 b:2}", visited)
+			}
+			if got := cache.Len(); got != 0 {
+				t.Fatalf("Len() after Purge = %d, want 0", got)
+			}
+			if _, err := cache.GetIFPresent("a"); err != KeyNotFoundError {
+				t.Fatalf("GetIFPresent(\"a\") after Purge = %v, want KeyNotFoundError", err)
+			}
+		})
+	}
+}
+
+// TestPurgeThenSetReusesStaleSlot is a regression guard for the
+// generation-based O(1) Purge: a key that was present before a Purge must
+// behave as a fresh insert afterwards, not resurrect the old value or
+// double-count length, even though the old slot is only lazily pruned.
+func TestPurgeThenSetReusesStaleSlot(t *testing.T) {
+	for _, tp := range []string{TYPE_SIMPLE, TYPE_LRU, TYPE_TWO_QUEUE, TYPE_SIEVE} {
+		t.Run(tp, func(t *testing.T) {
+			cache := New(10).EvictType(tp).Build()
+
+			cache.Set("key", "old")
+			cache.Purge()
+			if err := cache.Set("key", "new"); err != nil {
+				t.Fatalf("Set after Purge returned error: %v", err)
+			}
+
+			v, err := cache.GetIFPresent("key")
+			if err != nil {
+				t.Fatalf("GetIFPresent returned error: %v", err)
+			}
+			if v != "new" {
+				t.Fatalf("GetIFPresent = %v, want %q", v, "new")
+			}
+			if got := cache.Len(); got != 1 {
+				t.Fatalf("Len() = %d, want 1", got)
+			}
+		})
+	}
+}
+
+// TestPurgeThenRemoveStaleKey is a regression guard: removing a key that
+// only exists in a prior, purged generation must report KeyNotFoundError
+// rather than a stale success.
+func TestPurgeThenRemoveStaleKey(t *testing.T) {
+	for _, tp := range []string{TYPE_SIMPLE, TYPE_LRU, TYPE_TWO_QUEUE, TYPE_SIEVE} {
+		t.Run(tp, func(t *testing.T) {
+			cache := New(10).EvictType(tp).Build()
+
+			cache.Set("key", "value")
+			cache.Purge()
+
+			if err := cache.Remove("key"); err != KeyNotFoundError {
+				t.Fatalf("Remove after Purge = %v, want KeyNotFoundError", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package gcache
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// remover is implemented by every concrete cache policy's unexported
+// remove method, so the reaper and timing wheel can evict a key without
+// knowing which policy they're attached to.
+type remover interface {
+	remove(key interface{}) error
+}
+
+// WithReaper enables a background goroutine that sweeps the whole cache
+// for expired entries every interval, instead of relying on Get/evict to
+// notice them lazily. Mutually exclusive with WithTimingWheel; the last
+// one called wins.
+func (cb *CacheBuilder) WithReaper(interval time.Duration) *CacheBuilder {
+	cb.reaperInterval = interval
+	cb.wheelSlots = 0
+	return cb
+}
+
+// WithTimingWheel schedules each item's expiration into one of slots
+// buckets of width tick, as in go-zero's collection.Cache, so the reaper
+// only ever inspects the keys due to expire in the current tick instead of
+// scanning the whole cache. Mutually exclusive with WithReaper; the last
+// one called wins.
+func (cb *CacheBuilder) WithTimingWheel(tick time.Duration, slots int) *CacheBuilder {
+	cb.wheelTick = tick
+	cb.wheelSlots = slots
+	cb.reaperInterval = 0
+	return cb
+}
+
+// WithExpiryJitter multiplies every computed TTL by a value in
+// [1-fraction, 1+fraction], so entries inserted together don't all expire
+// in the same instant and stampede the loader.
+func (cb *CacheBuilder) WithExpiryJitter(fraction float64) *CacheBuilder {
+	cb.expiryJitter = fraction
+	return cb
+}
+
+// expiryTime applies the configured jitter (if any) to d and returns the
+// resulting absolute expiration time.
+func (c *baseCache) expiryTime(d time.Duration) time.Time {
+	if c.expiryJitter > 0 {
+		f := 1 - c.expiryJitter + rand.Float64()*2*c.expiryJitter
+		d = time.Duration(float64(d) * f)
+	}
+	return c.clock.Now().Add(d)
+}
+
+// scheduleExpiry registers key with the timing wheel, if one is
+// configured, so it is proactively removed around the time it expires.
+func (c *baseCache) scheduleExpiry(key interface{}, at time.Time) {
+	if c.wheel == nil {
+		return
+	}
+	c.wheel.schedule(key, at)
+}
+
+// startBackgroundExpiry starts whichever reaper was configured on the
+// builder. self lets the reaper and the snapshot code call back into the
+// concrete cache's remove/snapshotEntries/loadEntries methods. Starting
+// the reaper goroutine is a no-op if neither WithReaper nor
+// WithTimingWheel was called.
+func (c *baseCache) startBackgroundExpiry(self policyHooks) {
+	c.self = self
+
+	switch {
+	case c.wheelSlots > 0:
+		c.wheel = newTimingWheel(c.wheelTick, c.wheelSlots)
+		c.closeCh = make(chan struct{})
+		c.wg.Add(1)
+		go c.runTimingWheel()
+	case c.reaperInterval > 0:
+		c.closeCh = make(chan struct{})
+		c.wg.Add(1)
+		go c.runReaper()
+	}
+}
+
+func (c *baseCache) runReaper() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.reaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		case <-ticker.C:
+			if ec, ok := c.self.(expirySweeper); ok {
+				c.mu.Lock()
+				ec.reapExpired()
+				c.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (c *baseCache) runTimingWheel() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.wheel.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closeCh:
+			return
+		case <-ticker.C:
+			keys := c.wheel.slotFor(c.clock.Now())
+			if len(keys) == 0 {
+				continue
+			}
+			c.mu.Lock()
+			for _, key := range keys {
+				at, ok := c.self.expiresAt(key)
+				if !ok {
+					// Key was removed, replaced without an
+					// expiration, or already purged; nothing to do.
+					continue
+				}
+				if at.After(c.clock.Now()) {
+					// Not actually due yet: either its TTL exceeds the
+					// wheel's span and wrapped around early, or a later
+					// Set extended it. Re-schedule for its real
+					// expiration instead of evicting live data.
+					c.wheel.schedule(key, at)
+					continue
+				}
+				c.self.remove(key)
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Close stops the background reaper or timing wheel goroutine, if one was
+// started. It is safe to call multiple times and safe to call on a cache
+// that never had a reaper configured.
+func (c *baseCache) Close() error {
+	c.closeOnce.Do(func() {
+		if c.closeCh != nil {
+			close(c.closeCh)
+		}
+	})
+	c.wg.Wait()
+	return nil
+}
+
+// Stop is an alias for Close.
+func (c *baseCache) Stop() {
+	c.Close()
+}
+
+// expirySweeper is implemented by policies that support a full scan for
+// the plain interval-based reaper (as opposed to the timing wheel, which
+// tracks expirations itself).
+type expirySweeper interface {
+	reapExpired()
+}
+
+// timingWheel buckets keys by their absolute expiration time so a single
+// background goroutine can reap exactly the keys due this tick, without
+// scanning the whole cache.
+type timingWheel struct {
+	tick  time.Duration
+	slots int
+
+	mu      sync.Mutex
+	buckets [][]interface{}
+}
+
+func newTimingWheel(tick time.Duration, slots int) *timingWheel {
+	return &timingWheel{
+		tick:    tick,
+		slots:   slots,
+		buckets: make([][]interface{}, slots),
+	}
+}
+
+func (w *timingWheel) indexFor(t time.Time) int {
+	idx := (t.UnixNano() / int64(w.tick)) % int64(w.slots)
+	if idx < 0 {
+		idx += int64(w.slots)
+	}
+	return int(idx)
+}
+
+// schedule enqueues key into the bucket its expiration time falls in.
+func (w *timingWheel) schedule(key interface{}, at time.Time) {
+	idx := w.indexFor(at)
+
+	w.mu.Lock()
+	w.buckets[idx] = append(w.buckets[idx], key)
+	w.mu.Unlock()
+}
+
+// slotFor drains and returns the bucket for now, the bucket the wheel's
+// hand is currently passing over.
+func (w *timingWheel) slotFor(now time.Time) []interface{} {
+	idx := w.indexFor(now)
+
+	w.mu.Lock()
+	keys := w.buckets[idx]
+	w.buckets[idx] = nil
+	w.mu.Unlock()
+
+	return keys
+}
@@ -0,0 +1,89 @@
+package gcache
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCacheInterfaceExposesClose is a regression test: a caller who only
+// holds the Cache interface returned by Build must still be able to stop
+// a WithReaper/WithTimingWheel goroutine, without a type assertion to a
+// concrete policy.
+func TestCacheInterfaceExposesClose(t *testing.T) {
+	var cache Cache = New(10).LRU().WithReaper(5 * time.Millisecond).Build()
+
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+}
+
+func TestReaperSweepsExpiredEntries(t *testing.T) {
+	cache := New(10).LRU().WithReaper(5 * time.Millisecond).Build().(*LRUCache)
+	defer cache.Close()
+
+	if err := cache.SetWithExpire("key", "value", 10*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if got := cache.Len(); got != 0 {
+		t.Fatalf("Len() = %d after reaper should have swept the expired entry, want 0", got)
+	}
+}
+
+// TestTimingWheelDoesNotEvictBeforeActualExpiry is a regression test. The
+// wheel buckets keys by the absolute wall-clock nanosecond their
+// expiration falls in, modulo tick*slots, so an item whose TTL exceeds
+// the wheel's span lands in a bucket the hand passes through long before
+// the item is actually due (one span early, here). It must survive that
+// early pass and only be reaped once it has truly expired.
+func TestTimingWheelDoesNotEvictBeforeActualExpiry(t *testing.T) {
+	tick := 5 * time.Millisecond
+	slots := 20
+	span := tick * time.Duration(slots) // 100ms
+
+	cache := New(10).LRU().WithTimingWheel(tick, slots).Build().(*LRUCache)
+	defer cache.Close()
+
+	// Expires one span plus a bit: the hand passes its bucket ~10ms in
+	// (one span too early) before reaching it again near the real
+	// expiration at ~110ms.
+	if err := cache.SetWithExpire("key", "value", span+10*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := cache.Len(); got != 1 {
+		t.Fatalf("Len() = %d after the wheel's early pass over the item's bucket, want 1 (must not evict live data)", got)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if got := cache.Len(); got != 0 {
+		t.Fatalf("Len() = %d after the item's real expiry, want 0 (wheel should have reaped it)", got)
+	}
+}
+
+// TestTimingWheelHonorsExtendedTTL is a regression test: re-Set-ing a key
+// with a much longer TTL must not let the stale, short-TTL wheel entry
+// kill it when the hand reaches that earlier bucket.
+func TestTimingWheelHonorsExtendedTTL(t *testing.T) {
+	tick := 5 * time.Millisecond
+	slots := 10
+
+	cache := New(10).LRU().WithTimingWheel(tick, slots).Build().(*LRUCache)
+	defer cache.Close()
+
+	if err := cache.SetWithExpire("key", "value", 5*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+	if err := cache.SetWithExpire("key", "value", 5*tick*time.Duration(slots)); err != nil {
+		t.Fatalf("second SetWithExpire returned error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got := cache.Len(); got != 1 {
+		t.Fatalf("Len() = %d, want 1 (extended TTL must not be cut short by the stale schedule)", got)
+	}
+}
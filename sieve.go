@@ -0,0 +1,507 @@
+package gcache
+
+import (
+	"container/list"
+	"sync/atomic"
+	"time"
+)
+
+// SieveCache implements the SIEVE eviction policy: a single FIFO list of
+// entries with a "visited" bit, and a hand pointer that persists across
+// evictions. Because a hit only flips a bit instead of moving a list node,
+// SIEVE is cheaper than LRU under read-heavy workloads and needs no
+// reordering on the hot path.
+type SieveCache struct {
+	baseCache
+	items map[interface{}]*list.Element
+	list  *list.List
+	hand  *list.Element
+}
+
+type sieveItem struct {
+	clock Clock
+	key   interface{}
+	value interface{}
+	// visited is read and set with atomic loads/stores (0/1) instead of a plain
+	// bool, so a Get on an already-present key only needs c.mu's read lock.
+	visited    int32
+	expiration *time.Time
+	generation int64
+}
+
+func (it *sieveItem) setVisited(v bool) {
+	var n int32
+	if v {
+		n = 1
+	}
+	atomic.StoreInt32(&it.visited, n)
+}
+
+func (it *sieveItem) isVisited() bool {
+	return atomic.LoadInt32(&it.visited) == 1
+}
+
+func newSieveCache(cb *CacheBuilder) *SieveCache {
+	c := &SieveCache{}
+	buildCache(&c.baseCache, cb)
+
+	c.init()
+	c.loadGroup.cache = c
+	c.startBackgroundExpiry(c)
+	return c
+}
+
+func (c *SieveCache) init() {
+	c.list = list.New()
+	c.items = make(map[interface{}]*list.Element, c.capacity+1)
+	c.hand = nil
+}
+
+func (c *SieveCache) Set(key, value interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.set(key, value)
+	return err
+}
+
+func (c *SieveCache) SetWithExpire(key, value interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, err := c.set(key, value)
+	if err != nil {
+		return err
+	}
+
+	t := c.expiryTime(expiration)
+	item.(*sieveItem).expiration = &t
+	c.scheduleExpiry(key, t)
+	return nil
+}
+
+func (c *SieveCache) set(key, value interface{}) (interface{}, error) {
+	var err error
+	if c.serializeFunc != nil {
+		value, err = c.serializeFunc(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var item *sieveItem
+	if e, ok := c.items[key]; ok {
+		existing := e.Value.(*sieveItem)
+		if existing.generation == c.currentGeneration {
+			item = existing
+			oldValue := item.value
+			item.value = value
+			c.fireEviction(EvictionReasonReplaced, key, oldValue)
+		} else {
+			// Stale node left behind by a Purge; prune it and fall
+			// through to the insert path below.
+			if c.hand == e {
+				c.hand = e.Prev()
+			}
+			c.list.Remove(e)
+			delete(c.items, key)
+		}
+	}
+	if item == nil {
+		if c.length >= c.capacity {
+			c.evict(1)
+		}
+		item = &sieveItem{
+			clock:      c.clock,
+			key:        key,
+			value:      value,
+			generation: c.currentGeneration,
+		}
+		c.items[key] = c.list.PushFront(item)
+		c.length++
+	}
+
+	if c.expiration != nil {
+		t := c.expiryTime(*c.expiration)
+		item.expiration = &t
+		c.scheduleExpiry(key, t)
+	}
+
+	c.fireInsertion(key, value)
+
+	return item, nil
+}
+
+// pruneStale drops a node left behind by a Purge without firing an
+// eviction event, since it was already logically gone the moment the
+// generation advanced.
+func (c *SieveCache) pruneStale(e *list.Element) {
+	if c.hand == e {
+		c.hand = e.Prev()
+	}
+	c.list.Remove(e)
+	item := e.Value.(*sieveItem)
+	delete(c.items, item.key)
+}
+
+// evict walks backward from the hand, pruning stale nodes and clearing
+// visited bits, until it finds an unvisited live node to evict; the hand
+// is left at that node's predecessor so the next eviction resumes from
+// there.
+func (c *SieveCache) evict(count int) {
+	evicted := 0
+	for evicted < count {
+		o := c.hand
+		if o == nil {
+			o = c.list.Back()
+		}
+		if o == nil {
+			return
+		}
+
+		for o != nil {
+			item := o.Value.(*sieveItem)
+			if item.generation != c.currentGeneration {
+				next := o.Prev()
+				c.pruneStale(o)
+				o = next
+				if o == nil {
+					o = c.list.Back()
+				}
+				continue
+			}
+			if item.isVisited() {
+				item.setVisited(false)
+				o = o.Prev()
+				if o == nil {
+					o = c.list.Back()
+				}
+				continue
+			}
+			break
+		}
+		if o == nil {
+			return
+		}
+
+		c.hand = o.Prev()
+		c.removeElementWithReason(o, EvictionReasonCapacityReached)
+		evicted++
+	}
+}
+
+func (c *SieveCache) get(key interface{}, onLoad bool) (interface{}, error) {
+	e, ok := c.items[key]
+	if ok && e.Value.(*sieveItem).generation != c.currentGeneration {
+		// Stale node left behind by a Purge; prune it lazily.
+		c.pruneStale(e)
+		ok = false
+	}
+	if !ok {
+		if !onLoad {
+			c.stats.IncrMissCount()
+		}
+		return nil, KeyNotFoundError
+	}
+
+	item := e.Value.(*sieveItem)
+	if item.isExpired(nil) {
+		c.removeElementWithReason(e, EvictionReasonExpired)
+		if !onLoad {
+			c.stats.IncrMissCount()
+		}
+		return nil, KeyNotFoundError
+	}
+
+	item.setVisited(true)
+	if !onLoad {
+		c.stats.IncrHitCount()
+	}
+
+	if c.deserializeFunc != nil {
+		return c.deserializeFunc(key, item.value)
+	}
+	return item.value, nil
+}
+
+func (c *SieveCache) getWithLoader(key interface{}, isWait bool) (interface{}, error) {
+	if c.loaderExpireFunc == nil {
+		return nil, KeyNotFoundError
+	}
+
+	value, _, err := c.load(key, func(v interface{}, expiration *time.Duration, e error) (interface{}, error) {
+		if e != nil {
+			return nil, e
+		}
+
+		err := c.Set(key, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	}, isWait)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// getFast marks a hit as visited under only a read lock: the list
+// structure is untouched, and visited is updated with an atomic store, so
+// concurrent Gets on present keys never contend on a write lock.
+func (c *SieveCache) getFast(key interface{}) (interface{}, error) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	if !ok {
+		c.mu.RUnlock()
+		return nil, KeyNotFoundError
+	}
+	item := e.Value.(*sieveItem)
+	if item.generation != c.currentGeneration {
+		c.mu.RUnlock()
+		return nil, KeyNotFoundError
+	}
+	if item.isExpired(nil) {
+		c.mu.RUnlock()
+		return nil, KeyNotFoundError
+	}
+	item.setVisited(true)
+	value := item.value
+	c.mu.RUnlock()
+
+	c.stats.IncrHitCount()
+	if c.deserializeFunc != nil {
+		return c.deserializeFunc(key, value)
+	}
+	return value, nil
+}
+
+func (c *SieveCache) Get(key interface{}) (interface{}, error) {
+	if v, err := c.getFast(key); err == nil {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	v, err := c.get(key, false)
+	c.mu.Unlock()
+
+	if err == KeyNotFoundError {
+		return c.getWithLoader(key, true)
+	}
+	return v, err
+}
+
+func (c *SieveCache) GetIFPresent(key interface{}) (interface{}, error) {
+	if v, err := c.getFast(key); err == nil {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	v, err := c.get(key, false)
+	c.mu.Unlock()
+
+	if err == KeyNotFoundError {
+		return c.getWithLoader(key, false)
+	}
+	return v, err
+}
+
+func (c *SieveCache) GetALL() map[interface{}]interface{} {
+	c.mu.Lock()
+	allKeys := c.keys()
+	c.mu.Unlock()
+
+	m := make(map[interface{}]interface{})
+	for _, k := range allKeys {
+		v, err := c.GetIFPresent(k)
+		if err == nil {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// expiresAt reports key's current expiration time, for the timing wheel
+// to verify a scheduled key is actually due before removing it.
+func (c *SieveCache) expiresAt(key interface{}) (time.Time, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	item := e.Value.(*sieveItem)
+	if item.generation != c.currentGeneration || item.expiration == nil {
+		return time.Time{}, false
+	}
+	return *item.expiration, true
+}
+
+func (c *SieveCache) remove(key interface{}) error {
+	if e, ok := c.items[key]; ok {
+		if e.Value.(*sieveItem).generation != c.currentGeneration {
+			c.pruneStale(e)
+			return KeyNotFoundError
+		}
+		c.removeElementWithReason(e, EvictionReasonDeleted)
+		return nil
+	}
+	return KeyNotFoundError
+}
+
+func (c *SieveCache) Remove(key interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remove(key)
+}
+
+func (c *SieveCache) removeElementWithReason(e *list.Element, reason EvictionReason) {
+	if c.hand == e {
+		c.hand = e.Prev()
+	}
+	c.list.Remove(e)
+	item := e.Value.(*sieveItem)
+	delete(c.items, item.key)
+	if item.generation == c.currentGeneration {
+		c.length--
+	}
+	c.fireEviction(reason, item.key, item.value)
+}
+
+func (c *SieveCache) keys() []interface{} {
+	keys := make([]interface{}, 0, c.length)
+	for k, e := range c.items {
+		if e.Value.(*sieveItem).generation == c.currentGeneration {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (c *SieveCache) Keys() []interface{} {
+	c.mu.Lock()
+	allKeys := c.keys()
+	c.mu.Unlock()
+
+	keys := []interface{}{}
+	for _, k := range allKeys {
+		_, err := c.GetIFPresent(k)
+		if err == nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (c *SieveCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.length
+}
+
+// Purge just bumps the current generation and zeroes the logical length,
+// so it is O(1) and safe to call while other goroutines are mid-Get:
+// nodes from the prior generation are lazily pruned the next time
+// they're touched. The hand is reset since it may be pointing at a node
+// that's about to become stale.
+func (c *SieveCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.purgeVisitorFunc != nil {
+		for key, item := range c.items {
+			it := item.Value.(*sieveItem)
+			if it.generation == c.currentGeneration {
+				c.purgeVisitorFunc(key, it.value)
+			}
+		}
+	}
+
+	c.currentGeneration++
+	c.length = 0
+	c.hand = nil
+}
+
+func (it *sieveItem) isExpired(now *time.Time) bool {
+	if it.expiration == nil {
+		return false
+	}
+	if now == nil {
+		t := it.clock.Now()
+		now = &t
+	}
+	return it.expiration.Before(*now)
+}
+
+// snapshotEntries returns every live entry, including each one's visited
+// bit, for Save. It is called under c.mu's read lock.
+func (c *SieveCache) snapshotEntries() []snapshotEntry {
+	entries := make([]snapshotEntry, 0, c.length)
+	for e := c.list.Front(); e != nil; e = e.Next() {
+		it := e.Value.(*sieveItem)
+		if it.generation != c.currentGeneration {
+			continue
+		}
+		entries = append(entries, snapshotEntry{Key: it.key, Value: it.value, Expiration: it.expiration, Visited: it.isVisited()})
+	}
+	return entries
+}
+
+// loadEntries restores entries from Load, dropping any already past
+// their absolute expiration, and resets the hand since the old FIFO
+// positions no longer mean anything. It is called under c.mu's write
+// lock.
+func (c *SieveCache) loadEntries(entries []snapshotEntry) {
+	now := c.clock.Now()
+	for _, e := range entries {
+		if e.Expiration != nil && e.Expiration.Before(now) {
+			continue
+		}
+		if old, ok := c.items[e.Key]; ok {
+			c.list.Remove(old)
+			delete(c.items, e.Key)
+			if old.Value.(*sieveItem).generation == c.currentGeneration {
+				c.length--
+			}
+		}
+		item := &sieveItem{clock: c.clock, key: e.Key, value: e.Value, expiration: e.Expiration, generation: c.currentGeneration}
+		item.setVisited(e.Visited)
+		c.items[e.Key] = c.list.PushBack(item)
+		c.length++
+		if item.expiration != nil {
+			c.scheduleExpiry(e.Key, *item.expiration)
+		}
+	}
+	c.hand = nil
+}
+
+// reapExpired removes every currently-expired entry, pruning any stale
+// nodes left behind by a Purge along the way. It is called by the
+// background reaper under c.mu's write lock; it does not take the lock
+// itself.
+func (c *SieveCache) reapExpired() {
+	now := c.clock.Now()
+	for e := c.list.Back(); e != nil; {
+		prev := e.Prev()
+		it := e.Value.(*sieveItem)
+		if it.generation != c.currentGeneration {
+			c.pruneStale(e)
+		} else if it.isExpired(&now) {
+			c.removeElementWithReason(e, EvictionReasonExpired)
+		}
+		e = prev
+	}
+}
+
+func (c *SieveCache) Debug() map[string][]int {
+	d := make(map[string][]int)
+	d["sieve"] = []int{len(c.items), c.list.Len()}
+	return d
+}
+
+func (c *SieveCache) unsafeGet(key interface{}, onLoad bool) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key, onLoad)
+}
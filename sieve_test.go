@@ -0,0 +1,101 @@
+package gcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSieveCacheSetGet(t *testing.T) {
+	cache := New(10).Sieve().Build()
+
+	if err := cache.Set("key", "value"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	v, err := cache.Get("key")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if v != "value" {
+		t.Fatalf("Get returned %v, want %q", v, "value")
+	}
+}
+
+func TestSieveCacheEvictsOnCapacity(t *testing.T) {
+	evicted := make(map[interface{}]bool)
+	cache := New(2).Sieve().EvictedFunc(func(key, value interface{}) {
+		evicted[key] = true
+	}).Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	if cache.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cache.Len())
+	}
+	if len(evicted) == 0 {
+		t.Fatal("expected EvictedFunc to fire at least once when over capacity")
+	}
+}
+
+func TestSieveCacheExpire(t *testing.T) {
+	cache := New(10).Sieve().Build()
+
+	if err := cache.SetWithExpire("key", "value", 20*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := cache.GetIFPresent("key"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent after expiry returned %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestSieveCachePurge(t *testing.T) {
+	cache := New(10).Sieve().Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	cache.Purge()
+
+	if cache.Len() != 0 {
+		t.Fatalf("Len() after Purge = %d, want 0", cache.Len())
+	}
+	if _, err := cache.GetIFPresent("a"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent after Purge returned %v, want KeyNotFoundError", err)
+	}
+}
+
+// TestSieveCacheMissCountedOnce is a regression test: a genuine miss must
+// only increment MissCount once, even though Get/GetIFPresent try a fast,
+// read-locked lookup before falling back to the slow path.
+func TestSieveCacheMissCountedOnce(t *testing.T) {
+	cache := New(10).Sieve().Build()
+
+	if _, err := cache.GetIFPresent("missing"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent returned %v, want KeyNotFoundError", err)
+	}
+
+	if got := cache.MissCount(); got != 1 {
+		t.Fatalf("MissCount() = %d, want 1", got)
+	}
+	if got := cache.LookupCount(); got != 1 {
+		t.Fatalf("LookupCount() = %d, want 1", got)
+	}
+}
+
+func TestSieveCacheHitCountedOnce(t *testing.T) {
+	cache := New(10).Sieve().Build()
+	cache.Set("key", "value")
+
+	if _, err := cache.GetIFPresent("key"); err != nil {
+		t.Fatalf("GetIFPresent returned error: %v", err)
+	}
+
+	if got := cache.HitCount(); got != 1 {
+		t.Fatalf("HitCount() = %d, want 1", got)
+	}
+}
@@ -14,6 +14,7 @@ func newSimpleCache(cb *CacheBuilder) *SimpleCache {
 
 	c.init()
 	c.loadGroup.cache = c
+	c.startBackgroundExpiry(c)
 	return c
 }
 
@@ -42,8 +43,9 @@ func (c *SimpleCache) SetWithExpire(key, value interface{}, expiration time.Dura
 		return err
 	}
 
-	t := c.clock.Now().Add(expiration)
+	t := c.expiryTime(expiration)
 	item.(*simpleItem).expiration = &t
+	c.scheduleExpiry(key, t)
 	return nil
 }
 
@@ -56,30 +58,37 @@ func (c *SimpleCache) set(key, value interface{}) (interface{}, error) {
 		}
 	}
 
-	// Check for existing item
+	// Check for existing item. An entry from a prior generation is stale
+	// (the cache was Purge'd since it was inserted); treat it as absent.
 	item, ok := c.store[key]
+	if ok && item.generation != c.currentGeneration {
+		ok = false
+	}
 	if ok {
+		oldValue := item.value
 		item.value = value
+		c.fireEviction(EvictionReasonReplaced, key, oldValue)
 	} else {
 		// Verify size not exceeded
-		if (len(c.store) >= c.size) && c.size > 0 {
+		if (c.length >= c.size) && c.size > 0 {
 			c.evict(1)
 		}
 		item = &simpleItem{
-			clock: c.clock,
-			value: value,
+			clock:      c.clock,
+			value:      value,
+			generation: c.currentGeneration,
 		}
 		c.store[key] = item
+		c.length++
 	}
 
 	if c.expiration != nil {
-		t := c.clock.Now().Add(*c.expiration)
+		t := c.expiryTime(*c.expiration)
 		item.expiration = &t
+		c.scheduleExpiry(key, t)
 	}
 
-	if c.addedFunc != nil {
-		c.addedFunc(key, value)
-	}
+	c.fireInsertion(key, value)
 
 	return item, nil
 }
@@ -120,6 +129,11 @@ func (c *SimpleCache) GetIFPresent(key interface{}) (interface{}, error) {
 
 func (c *SimpleCache) get(key interface{}, onLoad bool) (interface{}, error) {
 	item, exists := c.store[key]
+	if exists && item.generation != c.currentGeneration {
+		// Stale entry left behind by a Purge; prune it lazily.
+		delete(c.store, key)
+		exists = false
+	}
 	if !exists {
 		if !onLoad {
 			c.stats.IncrMissCount()
@@ -128,7 +142,7 @@ func (c *SimpleCache) get(key interface{}, onLoad bool) (interface{}, error) {
 	}
 
 	if item.IsExpired(nil) {
-		c.remove(key)
+		c.removeWithReason(key, EvictionReasonExpired)
 		return nil, KeyNotFoundError
 	}
 
@@ -172,8 +186,14 @@ func (c *SimpleCache) evict(count int) {
 		if current >= count {
 			return
 		}
+		if item.generation != c.currentGeneration {
+			// Stale entry from a prior generation; prune it without
+			// counting it as an eviction.
+			delete(c.store, key)
+			continue
+		}
 		if item.expiration == nil || now.After(*item.expiration) {
-			defer c.remove(key)
+			defer c.removeWithReason(key, EvictionReasonCapacityReached)
 			current++
 		}
 	}
@@ -187,27 +207,47 @@ func (c *SimpleCache) Remove(key interface{}) error {
 	return c.remove(key)
 }
 
+// expiresAt reports key's current expiration time, for the timing wheel
+// to verify a scheduled key is actually due before removing it.
+func (c *SimpleCache) expiresAt(key interface{}) (time.Time, bool) {
+	item, ok := c.store[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	if item.generation != c.currentGeneration || item.expiration == nil {
+		return time.Time{}, false
+	}
+	return *item.expiration, true
+}
+
 func (c *SimpleCache) remove(key interface{}) error {
+	return c.removeWithReason(key, EvictionReasonDeleted)
+}
+
+func (c *SimpleCache) removeWithReason(key interface{}, reason EvictionReason) error {
 	item, ok := c.store[key]
-	if ok {
-		delete(c.store, key)
-		if c.evictedFunc != nil {
-			c.evictedFunc(key, item.value)
-		}
-		return nil
+	if !ok {
+		return KeyNotFoundError
+	}
+	delete(c.store, key)
+	if item.generation != c.currentGeneration {
+		// Already logically gone since the last Purge; nothing to report.
+		return KeyNotFoundError
 	}
-	return KeyNotFoundError
+	c.length--
+	c.fireEviction(reason, key, item.value)
+	return nil
 }
 
 // Returns a slice of the keys in the cache.
 func (c *SimpleCache) keys() []interface{} {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	keys := make([]interface{}, len(c.store))
-	var i = 0
-	for k := range c.store {
-		keys[i] = k
-		i++
+	keys := make([]interface{}, 0, c.length)
+	for k, item := range c.store {
+		if item.generation == c.currentGeneration {
+			keys = append(keys, k)
+		}
 	}
 	return keys
 }
@@ -238,27 +278,36 @@ func (c *SimpleCache) GetALL() map[interface{}]interface{} {
 
 // Returns the number of store in the cache.
 func (c *SimpleCache) Len() int {
-	return len(c.store)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.length
 }
 
-// Completely clear the cache
+// Completely clear the cache. This just bumps the current generation and
+// zeroes the logical length, so it is O(1) and safe to call while other
+// goroutines are mid-Get: entries from the prior generation are lazily
+// pruned the next time they're touched.
 func (c *SimpleCache) Purge() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.purgeVisitorFunc != nil {
 		for key, item := range c.store {
-			c.purgeVisitorFunc(key, item.value)
+			if item.generation == c.currentGeneration {
+				c.purgeVisitorFunc(key, item.value)
+			}
 		}
 	}
 
-	c.init()
+	c.currentGeneration++
+	c.length = 0
 }
 
 type simpleItem struct {
 	clock      Clock
 	value      interface{}
 	expiration *time.Time
+	generation int64
 }
 
 // returns boolean value whether this item is expired or not.
@@ -273,6 +322,56 @@ func (si *simpleItem) IsExpired(now *time.Time) bool {
 	return si.expiration.Before(*now)
 }
 
+// snapshotEntries returns every live entry for Save. It is called under
+// c.mu's read lock.
+func (c *SimpleCache) snapshotEntries() []snapshotEntry {
+	entries := make([]snapshotEntry, 0, c.length)
+	for key, item := range c.store {
+		if item.generation != c.currentGeneration {
+			continue
+		}
+		entries = append(entries, snapshotEntry{Key: key, Value: item.value, Expiration: item.expiration})
+	}
+	return entries
+}
+
+// loadEntries restores entries from Load, dropping any already past their
+// absolute expiration. It is called under c.mu's write lock.
+func (c *SimpleCache) loadEntries(entries []snapshotEntry) {
+	now := c.clock.Now()
+	for _, e := range entries {
+		if e.Expiration != nil && e.Expiration.Before(now) {
+			continue
+		}
+		if old, ok := c.store[e.Key]; ok && old.generation == c.currentGeneration {
+			c.length--
+		}
+		item := &simpleItem{clock: c.clock, value: e.Value, expiration: e.Expiration, generation: c.currentGeneration}
+		c.store[e.Key] = item
+		c.length++
+		if item.expiration != nil {
+			c.scheduleExpiry(e.Key, *item.expiration)
+		}
+	}
+}
+
+// reapExpired removes every currently-expired entry. It is called by the
+// background reaper under c.mu's write lock; it does not take the lock
+// itself.
+func (c *SimpleCache) reapExpired() {
+	now := c.clock.Now()
+	for key, item := range c.store {
+		if item.generation != c.currentGeneration {
+			// Stale entry from a prior generation; prune it.
+			delete(c.store, key)
+			continue
+		}
+		if item.expiration != nil && now.After(*item.expiration) {
+			c.removeWithReason(key, EvictionReasonExpired)
+		}
+	}
+}
+
 func (c *SimpleCache) Debug() map[string][]int {
 	d := make(map[string][]int)
 	d["simple"] = []int{len(c.store)}
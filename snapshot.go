@@ -0,0 +1,111 @@
+package gcache
+
+import (
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+type (
+	// EncodeFunc writes v to w. The default, set by WithCodec's absence,
+	// is encoding/gob.
+	EncodeFunc func(w io.Writer, v interface{}) error
+	// DecodeFunc reads a value previously written by an EncodeFunc into v.
+	DecodeFunc func(r io.Reader, v interface{}) error
+)
+
+func gobEncode(w io.Writer, v interface{}) error {
+	return gob.NewEncoder(w).Encode(v)
+}
+
+func gobDecode(r io.Reader, v interface{}) error {
+	return gob.NewDecoder(r).Decode(v)
+}
+
+// WithCodec overrides the encoding used by Save/Load (gob by default),
+// e.g. to swap in JSON or msgpack.
+func (cb *CacheBuilder) WithCodec(enc EncodeFunc, dec DecodeFunc) *CacheBuilder {
+	cb.encodeFunc = enc
+	cb.decodeFunc = dec
+	return cb
+}
+
+// snapshotEntry is the on-disk representation of one cache entry. Its
+// fields must stay exported for gob (or a user-supplied JSON/msgpack
+// codec) to see them. Frequent and Visited are only meaningful for the
+// policies that use them (TwoQueueCache and SieveCache, respectively) and
+// are ignored by the others.
+type snapshotEntry struct {
+	Key        interface{}
+	Value      interface{}
+	Expiration *time.Time
+	Frequent   bool
+	Visited    bool
+}
+
+// policyHooks is implemented by every concrete cache policy so the shared
+// baseCache code (reaper, snapshotting) can act on it without depending on
+// which policy it's embedded in.
+type policyHooks interface {
+	remover
+	snapshotEntries() []snapshotEntry
+	loadEntries(entries []snapshotEntry)
+	// expiresAt returns the current absolute expiration time for key and
+	// true, or false if key is no longer present or has no expiration.
+	// The timing wheel uses this to verify a key is actually due before
+	// removing it, since the key's TTL may have been extended (or
+	// cleared) by a Set since it was scheduled.
+	expiresAt(key interface{}) (time.Time, bool)
+}
+
+// Save writes a snapshot of the cache's current entries to w. Keys and
+// values of types other than gob's predeclared basic types (string, int,
+// ...) must have been registered with encoding/gob (via gob.Register)
+// unless WithCodec was used to select a different encoding.
+func (c *baseCache) Save(w io.Writer) error {
+	c.mu.RLock()
+	entries := c.self.snapshotEntries()
+	c.mu.RUnlock()
+
+	return c.encodeFunc(w, entries)
+}
+
+// Load replaces matching keys in the cache with the snapshot read from r.
+// Entries already past their absolute expiration are dropped instead of
+// being imported.
+func (c *baseCache) Load(r io.Reader) error {
+	var entries []snapshotEntry
+	if err := c.decodeFunc(r, &entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.self.loadEntries(entries)
+	c.mu.Unlock()
+	return nil
+}
+
+// SaveFile is a convenience wrapper around Save that writes to the named
+// file, creating or truncating it as needed.
+func (c *baseCache) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Save(f)
+}
+
+// LoadFile is a convenience wrapper around Load that reads from the named
+// file.
+func (c *baseCache) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.Load(f)
+}
@@ -0,0 +1,156 @@
+package gcache
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	cache.Set("a", "1")
+	cache.Set("b", "2")
+	if err := cache.SetWithExpire("c", "3", time.Hour); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	restored := New(10).LRU().Build().(*LRUCache)
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if got := restored.Len(); got != 3 {
+		t.Fatalf("Len() after Load = %d, want 3", got)
+	}
+
+	for key, want := range map[string]string{"a": "1", "b": "2", "c": "3"} {
+		v, err := restored.GetIFPresent(key)
+		if err != nil {
+			t.Fatalf("GetIFPresent(%q) returned error: %v", key, err)
+		}
+		if v != want {
+			t.Fatalf("GetIFPresent(%q) = %v, want %q", key, v, want)
+		}
+	}
+}
+
+func TestLoadDropsAlreadyExpiredEntries(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+
+	if err := cache.SetWithExpire("key", "value", 10*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	restored := New(10).LRU().Build().(*LRUCache)
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if got := restored.Len(); got != 0 {
+		t.Fatalf("Len() after Load of an expired snapshot = %d, want 0", got)
+	}
+}
+
+// TestLoadReplacesOverlappingKey is a regression test: Load into a cache
+// that already holds a key present in the snapshot must replace it in
+// place, not leak the old list node and over-count Len().
+func TestLoadReplacesOverlappingKey(t *testing.T) {
+	for _, tp := range []string{TYPE_SIMPLE, TYPE_LRU, TYPE_TWO_QUEUE, TYPE_SIEVE} {
+		t.Run(tp, func(t *testing.T) {
+			source := New(10).EvictType(tp).Build()
+			source.Set("k", "new")
+
+			var buf bytes.Buffer
+			if err := source.(interface{ Save(io.Writer) error }).Save(&buf); err != nil {
+				t.Fatalf("Save returned error: %v", err)
+			}
+
+			cache := New(10).EvictType(tp).Build()
+			cache.Set("k", "old")
+
+			if err := cache.(interface{ Load(io.Reader) error }).Load(&buf); err != nil {
+				t.Fatalf("Load returned error: %v", err)
+			}
+
+			if got := cache.Len(); got != 1 {
+				t.Fatalf("Len() after Load of an overlapping key = %d, want 1", got)
+			}
+
+			v, err := cache.GetIFPresent("k")
+			if err != nil {
+				t.Fatalf("GetIFPresent returned error: %v", err)
+			}
+			if v != "new" {
+				t.Fatalf("GetIFPresent = %v, want %q (Load should replace the existing value)", v, "new")
+			}
+		})
+	}
+}
+
+func TestSaveFileLoadFileRoundTrip(t *testing.T) {
+	cache := New(10).LRU().Build().(*LRUCache)
+	cache.Set("key", "value")
+
+	path := t.TempDir() + "/snapshot.gob"
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile returned error: %v", err)
+	}
+
+	restored := New(10).LRU().Build().(*LRUCache)
+	if err := restored.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+
+	v, err := restored.GetIFPresent("key")
+	if err != nil {
+		t.Fatalf("GetIFPresent returned error: %v", err)
+	}
+	if v != "value" {
+		t.Fatalf("GetIFPresent = %v, want %q", v, "value")
+	}
+}
+
+func TestWithCodecOverridesEncoding(t *testing.T) {
+	var encodeCalls, decodeCalls int
+	enc := func(w io.Writer, v interface{}) error {
+		encodeCalls++
+		return gobEncode(w, v)
+	}
+	dec := func(r io.Reader, v interface{}) error {
+		decodeCalls++
+		return gobDecode(r, v)
+	}
+
+	cache := New(10).LRU().WithCodec(enc, dec).Build().(*LRUCache)
+	cache.Set("key", "value")
+
+	var buf bytes.Buffer
+	if err := cache.Save(&buf); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if encodeCalls != 1 {
+		t.Fatalf("encodeCalls = %d, want 1", encodeCalls)
+	}
+
+	restored := New(10).LRU().WithCodec(enc, dec).Build().(*LRUCache)
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if decodeCalls != 1 {
+		t.Fatalf("decodeCalls = %d, want 1", decodeCalls)
+	}
+}
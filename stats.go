@@ -0,0 +1,51 @@
+package gcache
+
+import "sync/atomic"
+
+type statsAccessor interface {
+	HitCount() uint64
+	MissCount() uint64
+	LookupCount() uint64
+	HitRate() float64
+}
+
+// stats holds cache statistics.
+type stats struct {
+	hitCount  uint64
+	missCount uint64
+}
+
+// HitCount returns hit count.
+func (s *stats) HitCount() uint64 {
+	return atomic.LoadUint64(&s.hitCount)
+}
+
+// MissCount returns miss count.
+func (s *stats) MissCount() uint64 {
+	return atomic.LoadUint64(&s.missCount)
+}
+
+// LookupCount returns lookup count.
+func (s *stats) LookupCount() uint64 {
+	return s.HitCount() + s.MissCount()
+}
+
+// HitRate returns rate for cache hitting.
+func (s *stats) HitRate() float64 {
+	hc, mc := s.HitCount(), s.MissCount()
+	total := hc + mc
+	if total == 0 {
+		return 0
+	}
+	return float64(hc) / float64(total)
+}
+
+// IncrHitCount increments hit count.
+func (s *stats) IncrHitCount() uint64 {
+	return atomic.AddUint64(&s.hitCount, 1)
+}
+
+// IncrMissCount increments miss count.
+func (s *stats) IncrMissCount() uint64 {
+	return atomic.AddUint64(&s.missCount, 1)
+}
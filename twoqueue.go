@@ -0,0 +1,634 @@
+package gcache
+
+import (
+	"container/list"
+	"time"
+)
+
+const (
+	// TwoQueueDefaultRecentRatio is the default ratio of the total cache
+	// capacity given to the recent (Am) list.
+	TwoQueueDefaultRecentRatio = 0.25
+
+	// TwoQueueDefaultGhostRatio is the default ratio of the total cache
+	// capacity given to the ghost (A1out) list of evicted keys.
+	TwoQueueDefaultGhostRatio = 0.50
+)
+
+// TwoQueueCache implements the 2Q cache replacement policy, which tracks
+// recently-used and frequently-used entries in separate LRU lists so that a
+// single scan over unique keys cannot evict a working set, the way a plain
+// LRU can.
+type TwoQueueCache struct {
+	baseCache
+	recentRatio float64
+	ghostRatio  float64
+
+	recent      *list.List
+	recentMap   map[interface{}]*list.Element
+	frequent    *list.List
+	frequentMap map[interface{}]*list.Element
+	recentEvict *list.List
+	ghostMap    map[interface{}]*list.Element
+}
+
+type twoQueueItem struct {
+	clock      Clock
+	key        interface{}
+	value      interface{}
+	expiration *time.Time
+	generation int64
+}
+
+func newTwoQueueCache(cb *CacheBuilder) *TwoQueueCache {
+	c := &TwoQueueCache{}
+	buildCache(&c.baseCache, cb)
+
+	c.recentRatio = cb.recentRatio
+	c.ghostRatio = cb.ghostRatio
+
+	c.init()
+	c.loadGroup.cache = c
+	c.startBackgroundExpiry(c)
+	return c
+}
+
+// ghostEntry is the payload held by the A1out ghost list. It carries the
+// generation the key was evicted in, so a Purge invalidates ghost
+// membership along with everything else: a key ghosted before a Purge no
+// longer promotes straight into Amain on its next insert.
+type ghostEntry struct {
+	key        interface{}
+	generation int64
+}
+
+func (c *TwoQueueCache) init() {
+	c.recent = list.New()
+	c.recentMap = make(map[interface{}]*list.Element)
+	c.frequent = list.New()
+	c.frequentMap = make(map[interface{}]*list.Element)
+	c.recentEvict = list.New()
+	c.ghostMap = make(map[interface{}]*list.Element)
+}
+
+func (c *TwoQueueCache) Set(key, value interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.set(key, value)
+	return err
+}
+
+func (c *TwoQueueCache) SetWithExpire(key, value interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, err := c.set(key, value)
+	if err != nil {
+		return err
+	}
+
+	t := c.expiryTime(expiration)
+	item.(*twoQueueItem).expiration = &t
+	c.scheduleExpiry(key, t)
+	return nil
+}
+
+func (c *TwoQueueCache) set(key, value interface{}) (interface{}, error) {
+	var err error
+	if c.serializeFunc != nil {
+		value, err = c.serializeFunc(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Already cached in the frequent list: just update and move to front.
+	if it, ok := c.frequentMap[key]; ok {
+		item := it.Value.(*twoQueueItem)
+		if item.generation == c.currentGeneration {
+			oldValue := item.value
+			item.value = value
+			c.frequent.MoveToFront(it)
+			if c.expiration != nil {
+				t := c.expiryTime(*c.expiration)
+				item.expiration = &t
+				c.scheduleExpiry(key, t)
+			}
+			c.fireEviction(EvictionReasonReplaced, key, oldValue)
+			c.fireInsertion(key, value)
+			return item, nil
+		}
+		// Stale node left behind by a Purge; prune it and fall through
+		// to the insert path below.
+		c.frequent.Remove(it)
+		delete(c.frequentMap, key)
+	}
+
+	// Already cached in the recent list: just update in place.
+	if it, ok := c.recentMap[key]; ok {
+		item := it.Value.(*twoQueueItem)
+		if item.generation == c.currentGeneration {
+			oldValue := item.value
+			item.value = value
+			if c.expiration != nil {
+				t := c.expiryTime(*c.expiration)
+				item.expiration = &t
+				c.scheduleExpiry(key, t)
+			}
+			c.fireEviction(EvictionReasonReplaced, key, oldValue)
+			c.fireInsertion(key, value)
+			return item, nil
+		}
+		c.recent.Remove(it)
+		delete(c.recentMap, key)
+	}
+
+	item := &twoQueueItem{
+		clock:      c.clock,
+		key:        key,
+		value:      value,
+		generation: c.currentGeneration,
+	}
+	if c.expiration != nil {
+		t := c.expiryTime(*c.expiration)
+		item.expiration = &t
+		c.scheduleExpiry(key, t)
+	}
+
+	// A key that was recently evicted from Am is promoted straight into
+	// Amain, since seeing it again is evidence it is frequently used.
+	if ent, ok := c.ghostMap[key]; ok && ent.Value.(*ghostEntry).generation == c.currentGeneration {
+		c.recentEvict.Remove(ent)
+		delete(c.ghostMap, key)
+		c.ensureSpace()
+		c.frequentMap[key] = c.frequent.PushFront(item)
+	} else {
+		if ok {
+			// Ghost entry from a prior generation; prune it.
+			c.recentEvict.Remove(ent)
+			delete(c.ghostMap, key)
+		}
+		c.ensureSpace()
+		c.recentMap[key] = c.recent.PushFront(item)
+	}
+	c.length++
+
+	c.fireInsertion(key, value)
+
+	return item, nil
+}
+
+// ensureSpace evicts entries, if necessary, to make room for one more item.
+func (c *TwoQueueCache) ensureSpace() {
+	if c.length < c.capacity {
+		return
+	}
+	c.evict(1)
+}
+
+func (c *TwoQueueCache) recentCapacity() int {
+	return int(float64(c.capacity) * c.recentRatio)
+}
+
+func (c *TwoQueueCache) ghostCapacity() int {
+	return int(float64(c.capacity) * c.ghostRatio)
+}
+
+func (c *TwoQueueCache) evict(count int) {
+	for i := 0; i < count; i++ {
+		if c.recent.Len() > c.recentCapacity() {
+			if c.evictRecent() {
+				continue
+			}
+		}
+		if c.frequent.Len() > 0 && c.evictFrequent() {
+			continue
+		}
+		if c.recent.Len() > 0 && c.evictRecent() {
+			continue
+		}
+		return
+	}
+}
+
+// evictRecent evicts the least-recently-used live entry from Am, pruning
+// any stale nodes left behind by a Purge along the way, and pushes its key
+// onto the A1out ghost list, capped at ghostCapacity. It reports whether a
+// live entry was evicted.
+func (c *TwoQueueCache) evictRecent() bool {
+	for {
+		ent := c.recent.Back()
+		if ent == nil {
+			return false
+		}
+		c.recent.Remove(ent)
+		item := ent.Value.(*twoQueueItem)
+		delete(c.recentMap, item.key)
+		if item.generation != c.currentGeneration {
+			continue
+		}
+		c.length--
+
+		c.ghostMap[item.key] = c.recentEvict.PushFront(&ghostEntry{key: item.key, generation: c.currentGeneration})
+		if c.recentEvict.Len() > c.ghostCapacity() {
+			c.trimGhost()
+		}
+
+		c.fireEviction(EvictionReasonCapacityReached, item.key, item.value)
+		return true
+	}
+}
+
+// evictFrequent evicts the least-recently-used live entry from Amain,
+// pruning any stale nodes left behind by a Purge along the way. It
+// reports whether a live entry was evicted.
+func (c *TwoQueueCache) evictFrequent() bool {
+	for {
+		ent := c.frequent.Back()
+		if ent == nil {
+			return false
+		}
+		c.frequent.Remove(ent)
+		item := ent.Value.(*twoQueueItem)
+		delete(c.frequentMap, item.key)
+		if item.generation != c.currentGeneration {
+			continue
+		}
+		c.length--
+
+		c.fireEviction(EvictionReasonCapacityReached, item.key, item.value)
+		return true
+	}
+}
+
+func (c *TwoQueueCache) trimGhost() {
+	ent := c.recentEvict.Back()
+	if ent == nil {
+		return
+	}
+	c.recentEvict.Remove(ent)
+	delete(c.ghostMap, ent.Value.(*ghostEntry).key)
+}
+
+func (c *TwoQueueCache) get(key interface{}, onLoad bool) (interface{}, error) {
+	if it, ok := c.frequentMap[key]; ok {
+		item := it.Value.(*twoQueueItem)
+		if item.generation != c.currentGeneration {
+			// Stale node left behind by a Purge; prune it lazily.
+			c.frequent.Remove(it)
+			delete(c.frequentMap, key)
+		} else if item.isExpired(nil) {
+			c.removeFrequentElementWithReason(it, EvictionReasonExpired)
+			if !onLoad {
+				c.stats.IncrMissCount()
+			}
+			return nil, KeyNotFoundError
+		} else {
+			c.frequent.MoveToFront(it)
+			if !onLoad {
+				c.stats.IncrHitCount()
+			}
+			return c.deserialize(key, item.value)
+		}
+	}
+
+	if it, ok := c.recentMap[key]; ok {
+		item := it.Value.(*twoQueueItem)
+		if item.generation != c.currentGeneration {
+			c.recent.Remove(it)
+			delete(c.recentMap, key)
+		} else if item.isExpired(nil) {
+			c.removeRecentElementWithReason(it, EvictionReasonExpired)
+			if !onLoad {
+				c.stats.IncrMissCount()
+			}
+			return nil, KeyNotFoundError
+		} else {
+			// A hit on the recent list promotes the entry into the frequent list.
+			c.recent.Remove(it)
+			delete(c.recentMap, key)
+			c.frequentMap[key] = c.frequent.PushFront(item)
+			if !onLoad {
+				c.stats.IncrHitCount()
+			}
+			return c.deserialize(key, item.value)
+		}
+	}
+
+	if !onLoad {
+		c.stats.IncrMissCount()
+	}
+	return nil, KeyNotFoundError
+}
+
+func (c *TwoQueueCache) deserialize(key, value interface{}) (interface{}, error) {
+	if c.deserializeFunc != nil {
+		return c.deserializeFunc(key, value)
+	}
+	return value, nil
+}
+
+func (c *TwoQueueCache) getWithLoader(key interface{}, isWait bool) (interface{}, error) {
+	if c.loaderExpireFunc == nil {
+		return nil, KeyNotFoundError
+	}
+
+	value, _, err := c.load(key, func(v interface{}, expiration *time.Duration, e error) (interface{}, error) {
+		if e != nil {
+			return nil, e
+		}
+
+		err := c.Set(key, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	}, isWait)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *TwoQueueCache) Get(key interface{}) (interface{}, error) {
+	c.mu.Lock()
+	v, err := c.get(key, false)
+	c.mu.Unlock()
+
+	if err == KeyNotFoundError {
+		return c.getWithLoader(key, true)
+	}
+	return v, err
+}
+
+func (c *TwoQueueCache) GetIFPresent(key interface{}) (interface{}, error) {
+	c.mu.Lock()
+	v, err := c.get(key, false)
+	c.mu.Unlock()
+
+	if err == KeyNotFoundError {
+		return c.getWithLoader(key, false)
+	}
+	return v, err
+}
+
+func (c *TwoQueueCache) GetALL() map[interface{}]interface{} {
+	c.mu.Lock()
+	allKeys := c.keys()
+	c.mu.Unlock()
+
+	m := make(map[interface{}]interface{})
+	for _, k := range allKeys {
+		v, err := c.GetIFPresent(k)
+		if err == nil {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// expiresAt reports key's current expiration time, checking both the
+// frequent and recent queues, for the timing wheel to verify a scheduled
+// key is actually due before removing it.
+func (c *TwoQueueCache) expiresAt(key interface{}) (time.Time, bool) {
+	if ent, ok := c.frequentMap[key]; ok {
+		item := ent.Value.(*twoQueueItem)
+		if item.generation == c.currentGeneration && item.expiration != nil {
+			return *item.expiration, true
+		}
+		return time.Time{}, false
+	}
+	if ent, ok := c.recentMap[key]; ok {
+		item := ent.Value.(*twoQueueItem)
+		if item.generation == c.currentGeneration && item.expiration != nil {
+			return *item.expiration, true
+		}
+		return time.Time{}, false
+	}
+	return time.Time{}, false
+}
+
+func (c *TwoQueueCache) remove(key interface{}) error {
+	if ent, ok := c.frequentMap[key]; ok {
+		if ent.Value.(*twoQueueItem).generation != c.currentGeneration {
+			c.frequent.Remove(ent)
+			delete(c.frequentMap, key)
+			return KeyNotFoundError
+		}
+		c.removeFrequentElementWithReason(ent, EvictionReasonDeleted)
+		return nil
+	}
+	if ent, ok := c.recentMap[key]; ok {
+		if ent.Value.(*twoQueueItem).generation != c.currentGeneration {
+			c.recent.Remove(ent)
+			delete(c.recentMap, key)
+			return KeyNotFoundError
+		}
+		c.removeRecentElementWithReason(ent, EvictionReasonDeleted)
+		return nil
+	}
+	return KeyNotFoundError
+}
+
+func (c *TwoQueueCache) Remove(key interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.remove(key)
+}
+
+func (c *TwoQueueCache) removeFrequentElementWithReason(e *list.Element, reason EvictionReason) {
+	c.frequent.Remove(e)
+	item := e.Value.(*twoQueueItem)
+	delete(c.frequentMap, item.key)
+	if item.generation == c.currentGeneration {
+		c.length--
+	}
+	c.fireEviction(reason, item.key, item.value)
+}
+
+func (c *TwoQueueCache) removeRecentElementWithReason(e *list.Element, reason EvictionReason) {
+	c.recent.Remove(e)
+	item := e.Value.(*twoQueueItem)
+	delete(c.recentMap, item.key)
+	if item.generation == c.currentGeneration {
+		c.length--
+	}
+	c.fireEviction(reason, item.key, item.value)
+}
+
+func (c *TwoQueueCache) keys() []interface{} {
+	keys := make([]interface{}, 0, c.length)
+	for k, it := range c.recentMap {
+		if it.Value.(*twoQueueItem).generation == c.currentGeneration {
+			keys = append(keys, k)
+		}
+	}
+	for k, it := range c.frequentMap {
+		if it.Value.(*twoQueueItem).generation == c.currentGeneration {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (c *TwoQueueCache) Keys() []interface{} {
+	c.mu.Lock()
+	allKeys := c.keys()
+	c.mu.Unlock()
+
+	keys := []interface{}{}
+	for _, k := range allKeys {
+		_, err := c.GetIFPresent(k)
+		if err == nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (c *TwoQueueCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.length
+}
+
+// Purge just bumps the current generation and zeroes the logical length,
+// so it is O(1) and safe to call while other goroutines are mid-Get:
+// entries from the prior generation, including ghost-list membership, are
+// lazily pruned the next time they're touched.
+func (c *TwoQueueCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.purgeVisitorFunc != nil {
+		for _, item := range c.recentMap {
+			it := item.Value.(*twoQueueItem)
+			if it.generation == c.currentGeneration {
+				c.purgeVisitorFunc(it.key, it.value)
+			}
+		}
+		for _, item := range c.frequentMap {
+			it := item.Value.(*twoQueueItem)
+			if it.generation == c.currentGeneration {
+				c.purgeVisitorFunc(it.key, it.value)
+			}
+		}
+	}
+
+	c.currentGeneration++
+	c.length = 0
+}
+
+func (it *twoQueueItem) isExpired(now *time.Time) bool {
+	if it.expiration == nil {
+		return false
+	}
+	if now == nil {
+		t := it.clock.Now()
+		now = &t
+	}
+	return it.expiration.Before(*now)
+}
+
+// snapshotEntries returns every live entry from both Am and Amain for
+// Save, tagging frequent-list entries so loadEntries can put them back in
+// the right list. It is called under c.mu's read lock.
+func (c *TwoQueueCache) snapshotEntries() []snapshotEntry {
+	entries := make([]snapshotEntry, 0, c.length)
+	for e := c.frequent.Front(); e != nil; e = e.Next() {
+		it := e.Value.(*twoQueueItem)
+		if it.generation != c.currentGeneration {
+			continue
+		}
+		entries = append(entries, snapshotEntry{Key: it.key, Value: it.value, Expiration: it.expiration, Frequent: true})
+	}
+	for e := c.recent.Front(); e != nil; e = e.Next() {
+		it := e.Value.(*twoQueueItem)
+		if it.generation != c.currentGeneration {
+			continue
+		}
+		entries = append(entries, snapshotEntry{Key: it.key, Value: it.value, Expiration: it.expiration})
+	}
+	return entries
+}
+
+// loadEntries restores entries from Load into Amain or Am according to
+// Frequent, dropping any already past their absolute expiration. It is
+// called under c.mu's write lock.
+func (c *TwoQueueCache) loadEntries(entries []snapshotEntry) {
+	now := c.clock.Now()
+	for _, e := range entries {
+		if e.Expiration != nil && e.Expiration.Before(now) {
+			continue
+		}
+		if old, ok := c.frequentMap[e.Key]; ok {
+			c.frequent.Remove(old)
+			delete(c.frequentMap, e.Key)
+			if old.Value.(*twoQueueItem).generation == c.currentGeneration {
+				c.length--
+			}
+		} else if old, ok := c.recentMap[e.Key]; ok {
+			c.recent.Remove(old)
+			delete(c.recentMap, e.Key)
+			if old.Value.(*twoQueueItem).generation == c.currentGeneration {
+				c.length--
+			}
+		}
+		item := &twoQueueItem{clock: c.clock, key: e.Key, value: e.Value, expiration: e.Expiration, generation: c.currentGeneration}
+		if e.Frequent {
+			c.frequentMap[e.Key] = c.frequent.PushBack(item)
+		} else {
+			c.recentMap[e.Key] = c.recent.PushBack(item)
+		}
+		c.length++
+		if item.expiration != nil {
+			c.scheduleExpiry(e.Key, *item.expiration)
+		}
+	}
+}
+
+// reapExpired removes every currently-expired entry from both Am and
+// Amain, pruning any stale nodes left behind by a Purge along the way. It
+// is called by the background reaper under c.mu's write lock; it does not
+// take the lock itself.
+func (c *TwoQueueCache) reapExpired() {
+	now := c.clock.Now()
+	for e := c.recent.Back(); e != nil; {
+		prev := e.Prev()
+		it := e.Value.(*twoQueueItem)
+		if it.generation != c.currentGeneration {
+			c.recent.Remove(e)
+			delete(c.recentMap, it.key)
+		} else if it.isExpired(&now) {
+			c.removeRecentElementWithReason(e, EvictionReasonExpired)
+		}
+		e = prev
+	}
+	for e := c.frequent.Back(); e != nil; {
+		prev := e.Prev()
+		it := e.Value.(*twoQueueItem)
+		if it.generation != c.currentGeneration {
+			c.frequent.Remove(e)
+			delete(c.frequentMap, it.key)
+		} else if it.isExpired(&now) {
+			c.removeFrequentElementWithReason(e, EvictionReasonExpired)
+		}
+		e = prev
+	}
+}
+
+func (c *TwoQueueCache) Debug() map[string][]int {
+	d := make(map[string][]int)
+	d["2q"] = []int{c.recent.Len(), c.frequent.Len(), c.recentEvict.Len()}
+	return d
+}
+
+func (c *TwoQueueCache) unsafeGet(key interface{}, onLoad bool) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key, onLoad)
+}
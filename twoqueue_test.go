@@ -0,0 +1,94 @@
+package gcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTwoQueueCacheSetGet(t *testing.T) {
+	cache := New(10).TwoQueue().Build()
+
+	if err := cache.Set("key", "value"); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	v, err := cache.Get("key")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if v != "value" {
+		t.Fatalf("Get returned %v, want %q", v, "value")
+	}
+}
+
+func TestTwoQueueCacheGetMissing(t *testing.T) {
+	cache := New(10).TwoQueue().Build()
+
+	if _, err := cache.GetIFPresent("missing"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent on missing key returned %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestTwoQueueCacheEvictsOnCapacity(t *testing.T) {
+	evicted := make(map[interface{}]bool)
+	cache := New(2).TwoQueue().EvictedFunc(func(key, value interface{}) {
+		evicted[key] = true
+	}).Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	if cache.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", cache.Len())
+	}
+	if len(evicted) == 0 {
+		t.Fatal("expected EvictedFunc to fire at least once when over capacity")
+	}
+}
+
+func TestTwoQueueCacheExpire(t *testing.T) {
+	cache := New(10).TwoQueue().Build()
+
+	if err := cache.SetWithExpire("key", "value", 20*time.Millisecond); err != nil {
+		t.Fatalf("SetWithExpire returned error: %v", err)
+	}
+
+	if _, err := cache.GetIFPresent("key"); err != nil {
+		t.Fatalf("GetIFPresent before expiry returned error: %v", err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := cache.GetIFPresent("key"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent after expiry returned %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestTwoQueueCachePurge(t *testing.T) {
+	cache := New(10).TwoQueue().Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	cache.Purge()
+
+	if cache.Len() != 0 {
+		t.Fatalf("Len() after Purge = %d, want 0", cache.Len())
+	}
+	if _, err := cache.GetIFPresent("a"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent after Purge returned %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestTwoQueueCacheRemove(t *testing.T) {
+	cache := New(10).TwoQueue().Build()
+
+	cache.Set("key", "value")
+	if err := cache.Remove("key"); err != nil {
+		t.Fatalf("Remove returned error: %v", err)
+	}
+	if err := cache.Remove("key"); err != KeyNotFoundError {
+		t.Fatalf("second Remove returned %v, want KeyNotFoundError", err)
+	}
+}
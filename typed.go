@@ -0,0 +1,162 @@
+package gcache
+
+import "time"
+
+// TypedCache is a generics-based wrapper around Cache that removes the
+// interface{} boxing at the call site: keys and values keep their static
+// types all the way through Get/Set, so callers don't need a type
+// assertion after every lookup.
+type TypedCache[K comparable, V any] struct {
+	cache Cache
+}
+
+type (
+	// LoaderFuncT is the typed equivalent of LoaderFunc.
+	LoaderFuncT[K comparable, V any] func(K) (V, error)
+	// LoaderExpireFuncT is the typed equivalent of LoaderExpireFunc.
+	LoaderExpireFuncT[K comparable, V any] func(K) (V, *time.Duration, error)
+	// EvictedFuncT is the typed equivalent of EvictedFunc.
+	EvictedFuncT[K comparable, V any] func(K, V)
+	// AddedFuncT is the typed equivalent of AddedFunc.
+	AddedFuncT[K comparable, V any] func(K, V)
+	// SerializeFuncT is the typed equivalent of SerializeFunc.
+	SerializeFuncT[K comparable, V any] func(K, V) (interface{}, error)
+	// DeserializeFuncT is the typed equivalent of DeserializeFunc.
+	DeserializeFuncT[K comparable, V any] func(K, interface{}) (V, error)
+)
+
+// NewTyped wraps an untyped CacheBuilder-built Cache in a TypedCache[K, V].
+// The builder is configured exactly as for the untyped API (size, policy,
+// expiration, ...); NewTyped only builds it and wraps the result. Typed
+// callbacks are adapted separately with the Wrap* helpers below.
+func NewTyped[K comparable, V any](cb *CacheBuilder) *TypedCache[K, V] {
+	return &TypedCache[K, V]{cache: cb.Build()}
+}
+
+// WrapTyped adapts an already-built Cache, e.g. one shared with callers
+// that still use the untyped API.
+func WrapTyped[K comparable, V any](cache Cache) *TypedCache[K, V] {
+	return &TypedCache[K, V]{cache: cache}
+}
+
+func (t *TypedCache[K, V]) Set(key K, value V) error {
+	return t.cache.Set(key, value)
+}
+
+func (t *TypedCache[K, V]) SetWithExpire(key K, value V, expiration time.Duration) error {
+	return t.cache.SetWithExpire(key, value, expiration)
+}
+
+func (t *TypedCache[K, V]) Get(key K) (V, error) {
+	v, err := t.cache.Get(key)
+	return asTyped[V](v, err)
+}
+
+func (t *TypedCache[K, V]) GetIFPresent(key K) (V, error) {
+	v, err := t.cache.GetIFPresent(key)
+	return asTyped[V](v, err)
+}
+
+func (t *TypedCache[K, V]) GetALL() map[K]V {
+	m := make(map[K]V)
+	for k, v := range t.cache.GetALL() {
+		m[k.(K)] = v.(V)
+	}
+	return m
+}
+
+func (t *TypedCache[K, V]) Remove(key K) error {
+	return t.cache.Remove(key)
+}
+
+func (t *TypedCache[K, V]) Purge() {
+	t.cache.Purge()
+}
+
+func (t *TypedCache[K, V]) Keys() []K {
+	untyped := t.cache.Keys()
+	keys := make([]K, len(untyped))
+	for i, k := range untyped {
+		keys[i] = k.(K)
+	}
+	return keys
+}
+
+func (t *TypedCache[K, V]) Len() int {
+	return t.cache.Len()
+}
+
+func (t *TypedCache[K, V]) HitCount() uint64 {
+	return t.cache.HitCount()
+}
+
+func (t *TypedCache[K, V]) MissCount() uint64 {
+	return t.cache.MissCount()
+}
+
+func (t *TypedCache[K, V]) LookupCount() uint64 {
+	return t.cache.LookupCount()
+}
+
+func (t *TypedCache[K, V]) HitRate() float64 {
+	return t.cache.HitRate()
+}
+
+// asTyped converts an interface{} result from the untyped Cache back into
+// V, leaving the zero value for V on error so callers don't fault on a
+// failed type assertion against a nil interface.
+func asTyped[V any](v interface{}, err error) (V, error) {
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
+
+// WrapLoaderFunc adapts a typed loader to the interface{}-based LoaderFunc
+// expected by CacheBuilder.LoaderFunc.
+func WrapLoaderFunc[K comparable, V any](fn LoaderFuncT[K, V]) LoaderFunc {
+	return func(key interface{}) (interface{}, error) {
+		return fn(key.(K))
+	}
+}
+
+// WrapLoaderExpireFunc adapts a typed loader to the interface{}-based
+// LoaderExpireFunc expected by CacheBuilder.LoaderExpireFunc.
+func WrapLoaderExpireFunc[K comparable, V any](fn LoaderExpireFuncT[K, V]) LoaderExpireFunc {
+	return func(key interface{}) (interface{}, *time.Duration, error) {
+		return fn(key.(K))
+	}
+}
+
+// WrapEvictedFunc adapts a typed callback to the interface{}-based
+// EvictedFunc expected by CacheBuilder.EvictedFunc.
+func WrapEvictedFunc[K comparable, V any](fn EvictedFuncT[K, V]) EvictedFunc {
+	return func(key, value interface{}) {
+		fn(key.(K), value.(V))
+	}
+}
+
+// WrapAddedFunc adapts a typed callback to the interface{}-based AddedFunc
+// expected by CacheBuilder.AddedFunc.
+func WrapAddedFunc[K comparable, V any](fn AddedFuncT[K, V]) AddedFunc {
+	return func(key, value interface{}) {
+		fn(key.(K), value.(V))
+	}
+}
+
+// WrapSerializeFunc adapts a typed callback to the interface{}-based
+// SerializeFunc expected by CacheBuilder.SerializeFunc.
+func WrapSerializeFunc[K comparable, V any](fn SerializeFuncT[K, V]) SerializeFunc {
+	return func(key, value interface{}) (interface{}, error) {
+		return fn(key.(K), value.(V))
+	}
+}
+
+// WrapDeserializeFunc adapts a typed callback to the interface{}-based
+// DeserializeFunc expected by CacheBuilder.DeserializeFunc.
+func WrapDeserializeFunc[K comparable, V any](fn DeserializeFuncT[K, V]) DeserializeFunc {
+	return func(key, value interface{}) (interface{}, error) {
+		return fn(key.(K), value)
+	}
+}
@@ -0,0 +1,104 @@
+package gcache
+
+import (
+	"testing"
+)
+
+func TestTypedCacheSetGet(t *testing.T) {
+	tc := NewTyped[string, int](New(10).LRU())
+
+	if err := tc.Set("key", 42); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	v, err := tc.Get("key")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if v != 42 {
+		t.Fatalf("Get returned %d, want 42", v)
+	}
+}
+
+func TestTypedCacheGetMissing(t *testing.T) {
+	tc := NewTyped[string, int](New(10).LRU())
+
+	if _, err := tc.GetIFPresent("missing"); err != KeyNotFoundError {
+		t.Fatalf("GetIFPresent returned %v, want KeyNotFoundError", err)
+	}
+}
+
+func TestTypedCacheWithLoaderFunc(t *testing.T) {
+	loader := WrapLoaderFunc[string, int](func(key string) (int, error) {
+		return len(key), nil
+	})
+
+	cb := New(10).LRU().LoaderFunc(loader)
+	tc := NewTyped[string, int](cb)
+
+	v, err := tc.Get("hello")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if v != 5 {
+		t.Fatalf("Get returned %d, want 5", v)
+	}
+}
+
+func TestTypedCacheWithEvictedAndAddedFunc(t *testing.T) {
+	var addedKey string
+	var evictedKey string
+
+	added := WrapAddedFunc[string, int](func(key string, value int) {
+		addedKey = key
+	})
+	evicted := WrapEvictedFunc[string, int](func(key string, value int) {
+		evictedKey = key
+	})
+
+	cb := New(1).LRU().AddedFunc(added).EvictedFunc(evicted)
+	tc := NewTyped[string, int](cb)
+
+	tc.Set("a", 1)
+	tc.Set("b", 2)
+
+	if addedKey != "b" {
+		t.Fatalf("addedKey = %q, want %q", addedKey, "b")
+	}
+	if evictedKey != "a" {
+		t.Fatalf("evictedKey = %q, want %q", evictedKey, "a")
+	}
+}
+
+func TestWrapTyped(t *testing.T) {
+	cache := New(10).LRU().Build()
+	tc := WrapTyped[string, int](cache)
+
+	if err := tc.Set("key", 7); err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+
+	v, err := cache.Get("key")
+	if err != nil {
+		t.Fatalf("Get on underlying cache returned error: %v", err)
+	}
+	if v != 7 {
+		t.Fatalf("underlying Get returned %v, want 7", v)
+	}
+}
+
+func TestTypedCacheKeysAndGetALL(t *testing.T) {
+	tc := NewTyped[string, int](New(10).LRU())
+
+	tc.Set("a", 1)
+	tc.Set("b", 2)
+
+	if got := tc.Len(); got != 2 {
+		t.Fatalf("Len() = %d, want 2", got)
+	}
+
+	m := tc.GetALL()
+	if len(m) != 2 || m["a"] != 1 || m["b"] != 2 {
+		t.Fatalf("GetALL() = %v, want map[a:1 b:2]", m)
+	}
+}